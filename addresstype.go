@@ -0,0 +1,122 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddressType is the kind of delivery an address receives
+type AddressType int
+
+const (
+	// AddressTypeUnknown means the address couldn't be classified
+	AddressTypeUnknown AddressType = iota
+
+	// AddressTypeStreet is a house or building on a street ("123 MAIN ST")
+	AddressTypeStreet
+
+	// AddressTypePOBox is a Post Office box ("PO BOX 123")
+	AddressTypePOBox
+
+	// AddressTypeRuralRoute is a rural route box ("RR 2 BOX 152")
+	AddressTypeRuralRoute
+
+	// AddressTypeHighwayContract is a highway contract route box ("HC 68 BOX 23A")
+	AddressTypeHighwayContract
+
+	// AddressTypeGeneralDelivery is mail held at the post office for pickup ("GENERAL DELIVERY")
+	AddressTypeGeneralDelivery
+
+	// AddressTypeMilitary is an APO/FPO/DPO military address ("PSC 1234 BOX 5678")
+	AddressTypeMilitary
+)
+
+func (t AddressType) String() string {
+	switch t {
+	case AddressTypeUnknown:
+		return "unknown"
+	case AddressTypeStreet:
+		return "street"
+	case AddressTypePOBox:
+		return "PO box"
+	case AddressTypeRuralRoute:
+		return "rural route"
+	case AddressTypeHighwayContract:
+		return "highway contract"
+	case AddressTypeGeneralDelivery:
+		return "general delivery"
+	case AddressTypeMilitary:
+		return "military"
+	}
+	return fmt.Sprintf("AddressType(%d)", int(t))
+}
+
+// poBoxPrefixPattern matches the ways people write a PO Box: "P.O. Box", "POB", "Post Office Box"
+// The box number must have a digit or be a single letter, so "POBLANO RD" is left alone
+var poBoxPrefixPattern = regexp.MustCompile(`(?i)^(?:P\s?O\s?BOX|P\s?O\s?B|POST\s+OFFICE\s+BOX)\s*#?\s*(\d[\w-]*|[A-Z])(\s.*)?$`)
+
+// addressTypePatterns classify a street line by its form
+var addressTypePatterns = []struct {
+	Pattern *regexp.Regexp
+	Type    AddressType
+}{
+	{regexp.MustCompile(`(?i)^PO BOX\s`), AddressTypePOBox},
+	{regexp.MustCompile(`(?i)^(?:RR|RFD|RURAL\s+ROUTE|RURAL\s+RTE)\s*#?\s*\d`), AddressTypeRuralRoute},
+	{regexp.MustCompile(`(?i)^(?:HC|HCR|HIGHWAY\s+CONTRACT(?:\s+ROUTE)?|STAR\s+ROUTE)\s*#?\s*\d`), AddressTypeHighwayContract},
+	{regexp.MustCompile(`(?i)^GENERAL\s+DELIVERY$`), AddressTypeGeneralDelivery},
+}
+
+// Type classifies the address by the form of its street line
+func (a Address) Type() AddressType {
+	return classifyAddress(a, "")
+}
+
+// classifyAddress works out the address type from the street line and, if known, the carrier route
+// A recognizable street line wins; otherwise the carrier route prefix decides
+// (B is a PO Box section, G general delivery, R rural and H highway contract)
+func classifyAddress(address Address, carrierRoute string) AddressType {
+	if address.IsMilitary() {
+		return AddressTypeMilitary
+	}
+
+	street := collapseSpace(address.StreetAddress)
+	for _, p := range addressTypePatterns {
+		if p.Pattern.MatchString(street) {
+			return p.Type
+		}
+	}
+
+	route := strings.ToUpper(carrierRoute)
+	switch {
+	case strings.HasPrefix(route, "B"):
+		// Street addressing for PO boxes ("123 MAIN ST # 456") is still box delivery
+		return AddressTypePOBox
+	case strings.HasPrefix(route, "G"):
+		return AddressTypeGeneralDelivery
+	}
+
+	if startsWithDigit(strings.Fields(street)) {
+		return AddressTypeStreet
+	}
+
+	switch {
+	case strings.HasPrefix(route, "R"):
+		return AddressTypeRuralRoute
+	case strings.HasPrefix(route, "H"):
+		return AddressTypeHighwayContract
+	case strings.HasPrefix(route, "C"):
+		return AddressTypeStreet
+	}
+
+	return AddressTypeUnknown
+}
+
+// normalizePOBox rewrites the ways people write a PO Box into the USPS form ("PO BOX 123")
+func normalizePOBox(street string) string {
+	m := poBoxPrefixPattern.FindStringSubmatch(street)
+	if m == nil {
+		return street
+	}
+	return "PO BOX " + m[1] + m[2]
+}
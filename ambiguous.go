@@ -0,0 +1,149 @@
+package uspsaddr
+
+import (
+	"errors"
+	"strings"
+)
+
+// AmbiguityHint names a piece of input that would narrow an ambiguous address down to one match
+type AmbiguityHint string
+
+const (
+	// HintUrbanization means a Puerto Rico address needs its urbanization (URB)
+	HintUrbanization AmbiguityHint = "urbanization"
+
+	// HintZIPCode means the ZIP code is missing
+	HintZIPCode AmbiguityHint = "ZIP code"
+
+	// HintDirectional means the street has no directional ("N Main St" or "Main St NW")
+	HintDirectional AmbiguityHint = "directional"
+
+	// HintSuffix means the street has no suffix ("St", "Ave")
+	HintSuffix AmbiguityHint = "suffix"
+
+	// HintSecondary means there is no secondary unit ("Apt 15", "Ste 200")
+	HintSecondary AmbiguityHint = "secondary"
+)
+
+// Field returns the Address field the hint asks for
+func (h AmbiguityHint) Field() Field {
+	switch h {
+	case HintUrbanization:
+		return FieldUrbanization
+	case HintZIPCode:
+		return FieldZIPCode
+	case HintDirectional, HintSuffix:
+		return FieldStreetAddress
+	case HintSecondary:
+		return FieldSecondaryAddress
+	}
+	return ""
+}
+
+// AmbiguousAddressError is returned when USPS finds more than one address matching the input
+// Hints lists what extra input would pick one of them, so the user can be asked
+// "which one did you mean?" rather than being told the address wasn't found
+// It matches ErrMultipleAddresses, and the USPS error can be found with errors.As
+type AmbiguousAddressError struct {
+	// The input that was sent to USPS
+	Input Address
+
+	// Input that is missing and would resolve the ambiguity, most likely first
+	Hints []AmbiguityHint
+
+	// The error USPS returned
+	Err *Error
+}
+
+func (e *AmbiguousAddressError) Error() string {
+	if len(e.Hints) == 0 {
+		return "more than one address matches"
+	}
+	hints := make([]string, len(e.Hints))
+	for i, h := range e.Hints {
+		hints[i] = string(h)
+	}
+	list := hints[0]
+	if n := len(hints); n > 1 {
+		list = strings.Join(hints[:n-1], ", ") + " or " + hints[n-1]
+	}
+	return "more than one address matches; adding the " + list + " may pick one"
+}
+
+func (e *AmbiguousAddressError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AmbiguousAddressError) Is(target error) bool {
+	return target == ErrMultipleAddresses
+}
+
+// ambiguousAddressError turns a "more than one address was found" error from USPS into
+// an AmbiguousAddressError; other errors are returned unchanged
+func ambiguousAddressError(err error, input Address) error {
+	var uspsErr *Error
+	if !errors.As(err, &uspsErr) || !errors.Is(uspsErr, ErrMultipleAddresses) {
+		return err
+	}
+	return &AmbiguousAddressError{
+		Input: input,
+		Hints: ambiguityHints(input),
+		Err:   uspsErr,
+	}
+}
+
+// ambiguityHints lists the input that is missing from an ambiguous address, most likely first
+func ambiguityHints(input Address) []AmbiguityHint {
+	var hints []AmbiguityHint
+
+	if input.IsPuertoRico() && input.Urbanization == "" {
+		hints = append(hints, HintUrbanization)
+	}
+
+	if input.ZIPCode == "" {
+		hints = append(hints, HintZIPCode)
+	}
+
+	// Directionals and suffixes only apply to numbered streets, not PO Boxes, rural routes or
+	// Spanish street names ("150 CALLE A")
+	if input.Type() == AddressTypeStreet && !hasSpanishStreetType(strings.Fields(input.StreetAddress)) {
+		hasDirectional, hasSuffix := streetParts(input.StreetAddress)
+		if !hasDirectional {
+			hints = append(hints, HintDirectional)
+		}
+		if !hasSuffix {
+			hints = append(hints, HintSuffix)
+		}
+	}
+
+	if input.SecondaryAddress == "" {
+		hints = append(hints, HintSecondary)
+	}
+
+	return hints
+}
+
+// streetParts reports whether a street line has a pre or post-directional and a suffix,
+// looking in the same positions as standardizeStreet
+func streetParts(street string) (hasDirectional, hasSuffix bool) {
+	words := strings.Fields(street)
+	if len(words) < 2 {
+		return false, false
+	}
+
+	last := len(words) - 1
+	if _, ok := directional(words[last]); ok && last >= 2 {
+		hasDirectional = true
+		last--
+	}
+
+	if _, ok := streetSuffix(words[last]); ok && last >= 2 {
+		hasSuffix = true
+	}
+
+	if _, ok := directional(words[1]); ok && len(words) > 2 {
+		hasDirectional = true
+	}
+
+	return hasDirectional, hasSuffix
+}
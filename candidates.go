@@ -0,0 +1,288 @@
+package uspsaddr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CandidateKind is the change made to the input to find an alternative address
+type CandidateKind int
+
+const (
+	// CandidateSecondary means the secondary unit was dropped or moved onto the street line
+	CandidateSecondary CandidateKind = iota
+
+	// CandidateDirectional means a directional (N, S, E, W) was added or changed
+	CandidateDirectional
+
+	// CandidateSuffix means the street suffix (ST, AVE, RD) was added or changed
+	CandidateSuffix
+
+	// CandidateZIPCode means a nearby ZIP code for the same city was used
+	CandidateZIPCode
+)
+
+func (k CandidateKind) String() string {
+	switch k {
+	case CandidateSecondary:
+		return "secondary"
+	case CandidateDirectional:
+		return "directional"
+	case CandidateSuffix:
+		return "suffix"
+	case CandidateZIPCode:
+		return "ZIP code"
+	}
+	return fmt.Sprintf("CandidateKind(%d)", int(k))
+}
+
+// Candidate describes how the input was changed to find an alternative address
+// The changed input that USPS matched is in ValidationResult.Input
+type Candidate struct {
+	Kind CandidateKind
+
+	// What was changed, such as "added directional N" or "changed ZIP code to 80303"
+	Description string
+
+	// Position in the ranked list of alternatives, starting at 1
+	Rank int
+}
+
+// candidateDirectionals are the directionals tried on a street line
+var candidateDirectionals = []string{"N", "S", "E", "W"}
+
+// candidateSuffixes are the street suffixes tried on a street line
+var candidateSuffixes = []string{"ST", "AVE", "RD"}
+
+// nearbyZIPOffsets are how far from the input ZIP code to look for another ZIP code in the same city
+var nearbyZIPOffsets = []int{1, -1, 2, -2}
+
+// variant is a changed copy of the input to try
+type variant struct {
+	Address   Address
+	Candidate Candidate
+}
+
+// needsCandidates reports whether a validation found nothing, or more than one address
+func needsCandidates(result *ValidationResult, err error) bool {
+	if err != nil {
+		return errors.Is(err, ErrAddressNotFound) || errors.Is(err, ErrMultipleAddresses)
+	}
+	// Correction 22 means USPS found more than one match and picked one
+	return hasCorrection(*result, "22")
+}
+
+// findCandidates validates variants of the input, spending at most Config.CandidateBudget
+// requests, and returns the DPV-confirmed results ranked best first
+// Results that match the original result, if there was one, are left out
+func (c *Client) findCandidates(ctx context.Context, prepared preparedAddress, original *ValidationResult) []ValidationResult {
+	budget := c.config.CandidateBudget
+	input := prepared.Address
+
+	var results []ValidationResult
+	seen := map[Address]bool{}
+	if original != nil {
+		seen[original.Address] = true
+	}
+
+	// try validates a variant, returning false once the search should stop
+	try := func(v variant) bool {
+		if v.Address.Validate() != nil {
+			return true
+		}
+		if budget <= 0 {
+			return false
+		}
+		budget--
+
+		// The variant isn't the street line and secondary that were split, if any
+		p := prepared
+		p.Address = v.Address
+		p.SecondarySplit = nil
+		result, err := c.validate(ctx, p)
+		if err != nil {
+			return !stopSearch(err)
+		}
+		if !dpvConfirmed(*result) || seen[result.Address] {
+			return true
+		}
+		seen[result.Address] = true
+
+		candidate := v.Candidate
+		result.Candidate = &candidate
+		results = append(results, *result)
+		return true
+	}
+
+	for _, v := range candidateVariants(input) {
+		if !try(v) {
+			return rankCandidates(results)
+		}
+	}
+
+	// Nearby ZIP codes cost a city-state lookup each, so they are tried last
+	if input.City != "" && zipCodePattern.MatchString(input.ZIPCode) {
+		zip, _ := strconv.Atoi(input.ZIPCode)
+		for _, offset := range nearbyZIPOffsets {
+			if budget < 2 {
+				break
+			}
+			nearby := fmt.Sprintf("%05d", zip+offset)
+			if zip+offset <= 0 || len(nearby) != 5 {
+				continue
+			}
+
+			budget--
+			cs, err := c.LookupCityState(ctx, nearby)
+			if err != nil {
+				if stopSearch(err) {
+					break
+				}
+				continue
+			}
+			if !strings.EqualFold(cs.City, input.City) || !strings.EqualFold(cs.State, input.State) {
+				continue
+			}
+
+			address := input
+			address.ZIPCode = nearby
+			address.ZIPPlus4 = ""
+			if !try(variant{address, Candidate{Kind: CandidateZIPCode, Description: "changed ZIP code to " + nearby}}) {
+				break
+			}
+		}
+	}
+
+	return rankCandidates(results)
+}
+
+// stopSearch reports whether an error should end the search for candidates
+// Variants USPS doesn't recognize are skipped, but rate limits and outages stop the search
+func stopSearch(err error) bool {
+	return !errors.Is(err, ErrAddressNotFound) && !errors.Is(err, ErrMultipleAddresses) && !errors.Is(err, ErrInvalidInput)
+}
+
+// candidateVariants returns the changed copies of the input to try, most likely first
+func candidateVariants(input Address) []variant {
+	var variants []variant
+	add := func(kind CandidateKind, description string, address Address) {
+		variants = append(variants, variant{address, Candidate{Kind: kind, Description: description}})
+	}
+
+	// A unit that doesn't exist stops an otherwise good address from matching
+	if input.SecondaryAddress != "" {
+		dropped := input
+		dropped.SecondaryAddress = ""
+		add(CandidateSecondary, "dropped secondary "+input.SecondaryAddress, dropped)
+
+		moved := input
+		moved.StreetAddress = input.StreetAddress + " " + input.SecondaryAddress
+		moved.SecondaryAddress = ""
+		add(CandidateSecondary, "moved secondary "+input.SecondaryAddress+" to the street address", moved)
+	}
+
+	// Directionals and suffixes only apply to numbered streets, as in ambiguityHints
+	words := strings.Fields(input.StreetAddress)
+	if input.Type() != AddressTypeStreet || hasSpanishStreetType(words) || len(words) < 2 {
+		return variants
+	}
+
+	// The suffix is the last word, or the one before a post-directional ("100 MAIN ST NW")
+	last := len(words) - 1
+	_, hasPostDirectional := directional(words[last])
+	hasPostDirectional = hasPostDirectional && last >= 2
+	if hasPostDirectional {
+		last--
+	}
+
+	// Change whichever directional the street has, or add one after the house number ("100 N MAIN ST")
+	at := -1
+	if _, ok := directional(words[1]); ok && len(words) > 2 {
+		at = 1
+	} else if hasPostDirectional {
+		at = len(words) - 1
+	}
+	var current string
+	if at >= 0 {
+		current, _ = directional(words[at])
+	}
+	for _, dir := range candidateDirectionals {
+		if dir == current {
+			continue
+		}
+		address := input
+		if at >= 0 {
+			address.StreetAddress = replaceWord(words, at, dir)
+			add(CandidateDirectional, "changed directional to "+dir, address)
+		} else {
+			address.StreetAddress = insertWord(words, 1, dir)
+			add(CandidateDirectional, "added directional "+dir, address)
+		}
+	}
+
+	currentSuffix, hasSuffix := streetSuffix(words[last])
+	hasSuffix = hasSuffix && last >= 2
+	for _, suffix := range candidateSuffixes {
+		if suffix == currentSuffix {
+			continue
+		}
+		address := input
+		if hasSuffix {
+			address.StreetAddress = replaceWord(words, last, suffix)
+			add(CandidateSuffix, "changed suffix to "+suffix, address)
+		} else {
+			address.StreetAddress = insertWord(words, last+1, suffix)
+			add(CandidateSuffix, "added suffix "+suffix, address)
+		}
+	}
+
+	return variants
+}
+
+// replaceWord returns the words joined with words[i] replaced
+func replaceWord(words []string, i int, word string) string {
+	changed := append([]string(nil), words...)
+	changed[i] = word
+	return strings.Join(changed, " ")
+}
+
+// insertWord returns the words joined with word inserted before words[i]
+func insertWord(words []string, i int, word string) string {
+	changed := append(append(append([]string(nil), words[:i]...), word), words[i:]...)
+	return strings.Join(changed, " ")
+}
+
+// dpvConfirmed reports whether USPS confirmed the address as deliverable
+func dpvConfirmed(result ValidationResult) bool {
+	if result.AdditionalInfo == nil {
+		return false
+	}
+	return result.AdditionalInfo.DPVConfirmation.Confirmed()
+}
+
+// dpvRanks orders the confirmed DPV indicators from best to worst
+var dpvRanks = map[DPV]int{
+	DPVConfirmed:            0,
+	DPVSecondaryUnconfirmed: 1,
+	DPVMissingSecondary:     2,
+}
+
+// rankCandidates sorts the alternatives best first: by DPV confirmation, then by the number of
+// corrections USPS suggested, keeping the order they were tried in otherwise
+func rankCandidates(results []ValidationResult) []ValidationResult {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := dpvRanks[results[i].AdditionalInfo.DPVConfirmation], dpvRanks[results[j].AdditionalInfo.DPVConfirmation]
+		if a != b {
+			return a < b
+		}
+		return len(results[i].Corrections) < len(results[j].Corrections)
+	})
+	for i := range results {
+		results[i].Candidate.Rank = i + 1
+	}
+	return results
+}
@@ -0,0 +1,233 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// CodeKind says whether a code is a correction or a match code
+type CodeKind int
+
+const (
+	// CodeKindCorrection is a code from ValidationResult.Corrections
+	CodeKindCorrection CodeKind = iota
+
+	// CodeKindMatch is a code from ValidationResult.Matches
+	CodeKindMatch
+)
+
+func (k CodeKind) String() string {
+	switch k {
+	case CodeKindCorrection:
+		return "correction"
+	case CodeKindMatch:
+		return "match"
+	}
+	return fmt.Sprintf("CodeKind(%d)", int(k))
+}
+
+// Severity is how much a correction or match code matters to the user
+type Severity int
+
+const (
+	// SeverityInfo needs nothing from the user
+	SeverityInfo Severity = iota
+
+	// SeverityWarning means the address can be used, but the user should check it
+	SeverityWarning
+
+	// SeverityError means the address can't be used until the user fixes it
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Action is what the user should do about a correction or match code
+type Action int
+
+const (
+	// ActionNone means nothing needs to be done
+	ActionNone Action = iota
+
+	// ActionAddSecondary means an apartment, suite or box number should be added
+	ActionAddSecondary
+
+	// ActionCheckSecondary means the apartment, suite or box number should be checked
+	ActionCheckSecondary
+
+	// ActionAddDetail means more of the address (directional, suffix, ZIP code) should be given
+	// so a single address matches
+	ActionAddDetail
+
+	// ActionReview means the code isn't in the catalog, so the user should review the address
+	ActionReview
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionNone:
+		return "none"
+	case ActionAddSecondary:
+		return "add secondary"
+	case ActionCheckSecondary:
+		return "check secondary"
+	case ActionAddDetail:
+		return "add detail"
+	case ActionReview:
+		return "review"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// DefaultLocale is the language used when Config.Locale is empty or has no message
+const DefaultLocale = "en"
+
+// CodeInfo describes a correction or match code returned by USPS
+type CodeInfo struct {
+	Kind CodeKind
+	Code string
+
+	// The DPV indicator this entry is specific to; DPVUnknown means it applies to any
+	DPV DPV
+
+	Severity Severity
+	Action   Action
+
+	// User messages by language ("en", "es")
+	Messages map[string]string
+}
+
+// Message returns the user message for a locale such as "es" or "es-MX", falling back to the
+// base language and then to English
+func (i CodeInfo) Message(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if message, ok := i.Messages[locale]; ok {
+		return message
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		if message, ok := i.Messages[base]; ok {
+			return message
+		}
+	}
+	return i.Messages[DefaultLocale]
+}
+
+// MessageOverride can replace the catalog's user message for a code (see Config.MessageOverride)
+// It returns the message to use, or "" to keep the catalog's
+type MessageOverride func(info CodeInfo, locale string) string
+
+// codeCatalog lists the correction and match codes documented in the USPS spec
+// Entries for a specific DPV indicator come before the general entry for the same code
+var codeCatalog = []CodeInfo{
+	{
+		Kind:     CodeKindCorrection,
+		Code:     "22",
+		Severity: SeverityError,
+		Action:   ActionAddDetail,
+		Messages: map[string]string{
+			"en": "More than one address matches what you entered. Please add more detail, such as a direction (N, S, E or W), the street type or the ZIP code.",
+			"es": "Más de una dirección coincide con lo que ingresó. Agregue más detalles, como el punto cardinal (norte, sur, este u oeste), el tipo de calle o el código postal.",
+		},
+	},
+	{
+		Kind:     CodeKindCorrection,
+		Code:     "32",
+		DPV:      DPVSecondaryUnconfirmed,
+		Severity: SeverityWarning,
+		Action:   ActionCheckSecondary,
+		Messages: map[string]string{
+			"en": "We couldn't confirm the apartment, suite or unit number. Please double-check what you entered.",
+			"es": "No pudimos confirmar el número de apartamento, suite o unidad. Verifique lo que ingresó.",
+		},
+	},
+	{
+		Kind:     CodeKindCorrection,
+		Code:     "32",
+		Severity: SeverityWarning,
+		Action:   ActionAddSecondary,
+		Messages: map[string]string{
+			"en": "We found this address, but it needs an apartment, suite or box number. Please add it.",
+			"es": "Encontramos esta dirección, pero le falta el número de apartamento, suite o apartado. Agréguelo, por favor.",
+		},
+	},
+	{
+		Kind:     CodeKindMatch,
+		Code:     "31",
+		Severity: SeverityInfo,
+		Action:   ActionNone,
+		Messages: map[string]string{
+			"en": "The address was matched exactly.",
+			"es": "La dirección coincide exactamente.",
+		},
+	},
+}
+
+// LookupCode returns the catalog entry for a correction or match code, given the result's DPV
+// indicator
+// For a code that isn't in the catalog it returns an entry using the USPS text as the English
+// message, with ActionReview, and false
+// The entry is a copy, so changing its Messages doesn't change the catalog
+func LookupCode(kind CodeKind, code string, dpv DPV, text string) (CodeInfo, bool) {
+	for _, info := range codeCatalog {
+		if info.Kind == kind && info.Code == code && (info.DPV == DPVUnknown || info.DPV == dpv) {
+			info.Messages = maps.Clone(info.Messages)
+			return info, true
+		}
+	}
+
+	info := CodeInfo{
+		Kind:     kind,
+		Code:     code,
+		Severity: SeverityWarning,
+		Action:   ActionReview,
+		Messages: map[string]string{DefaultLocale: text},
+	}
+	if kind == CodeKindMatch {
+		info.Severity = SeverityInfo
+	}
+	return info, false
+}
+
+// describeCodes fills in the severity, action and user message of each correction and match code
+func describeCodes(result *ValidationResult, locale string, override MessageOverride) {
+	var dpv DPV
+	if result.AdditionalInfo != nil {
+		dpv = result.AdditionalInfo.DPVConfirmation
+	}
+
+	message := func(info CodeInfo) string {
+		if override != nil {
+			if message := override(info, locale); message != "" {
+				return message
+			}
+		}
+		return info.Message(locale)
+	}
+
+	for i := range result.Corrections {
+		c := &result.Corrections[i]
+		info, _ := LookupCode(CodeKindCorrection, c.Code, dpv, c.Text)
+		c.Severity = info.Severity
+		c.Action = info.Action
+		c.UserMessage = message(info)
+	}
+
+	for i := range result.Matches {
+		m := &result.Matches[i]
+		info, _ := LookupCode(CodeKindMatch, m.Code, dpv, m.Text)
+		m.Severity = info.Severity
+		m.Action = info.Action
+		m.UserMessage = message(info)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strings"
+	"regexp"
 	"time"
 
 	"github.com/tadhunt/logger"
@@ -12,6 +12,9 @@ import (
 	"github.com/tadhunt/uspsaddr/uspsinternal"
 )
 
+// zipCodePattern matches the 5-digit ZIPCode parameter pattern from the USPS spec
+var zipCodePattern = regexp.MustCompile(`^\d{5}$`)
+
 // Client provides address validation using the USPS API
 type Client struct {
 	config       Config
@@ -76,45 +79,91 @@ func NewClient(config Config) (*Client, error) {
 }
 
 // ValidateAddress validates and canonicalizes an address
-// Returns an array of validation results (typically one, but may be multiple for ambiguous addresses)
+// Returns an array of validation results: typically one, but if Config.CandidateBudget is set and
+// the address is ambiguous or not found, any alternatives that were found are added (see Candidate)
+// If the input itself didn't match, its error (such as an *AmbiguousAddressError) is returned
+// along with the alternatives, so errors.Is and the hints still work
 func (c *Client) ValidateAddress(ctx context.Context, address *Address) ([]ValidationResult, error) {
 	if address == nil {
-		return nil, fmt.Errorf("address cannot be nil")
+		return nil, invalidInputf("address cannot be nil")
 	}
 
-	// Validate required fields
-	if address.StreetAddress == "" {
-		return nil, fmt.Errorf("street address is required")
+	// Clean up the input before checking it
+	prepared, err := c.prepare(*address)
+	if err != nil {
+		return nil, err
 	}
+	input := prepared.Address
 
-	params := &uspsinternal.GetAddressParams{
-		StreetAddress: address.StreetAddress,
+	// Check everything USPS would reject before spending a request
+	if err := input.Validate(); err != nil {
+		return nil, err
 	}
 
-	if address.State == "" {
-		return nil, fmt.Errorf("2 letter state abbreviation is required")
+	if prepared.Military {
+		if err := checkMilitary(input); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(address.State) != 2 {
-		return nil, fmt.Errorf("2 letter state abbreviation is required")
+	if c.config.CheckZIPState {
+		if err := CheckZIPState(input.ZIPCode, input.State); err != nil {
+			return nil, err
+		}
 	}
 
-	params.State = strings.ToUpper(address.State)
+	result, err := c.validate(ctx, prepared)
 
-	if address.SecondaryAddress != "" {
-		params.SecondaryAddress = &address.SecondaryAddress
+	var results []ValidationResult
+	if err == nil {
+		results = append(results, *result)
 	}
-	if address.City != "" {
-		params.City = &address.City
+
+	// Look for alternatives the input may have meant
+	if c.config.CandidateBudget > 0 && needsCandidates(result, err) {
+		results = append(results, c.findCandidates(ctx, prepared, result)...)
 	}
-	if address.ZIPCode != "" {
-		params.ZIPCode = &address.ZIPCode
+
+	if err != nil {
+		// Rejected alternatives are dropped, but the input's own error is the one to report
+		if c.config.Policy != nil {
+			results, _ = applyPolicy(c.config.Policy, results)
+		}
+		return results, err
 	}
-	if address.Firm != "" {
-		params.Firm = &address.Firm
+
+	if c.config.Policy != nil {
+		return applyPolicy(c.config.Policy, results)
 	}
-	if address.Urbanization != "" {
-		params.Urbanization = &address.Urbanization
+	return results, nil
+}
+
+// validate sends a prepared address to USPS
+func (c *Client) validate(ctx context.Context, prepared preparedAddress) (*ValidationResult, error) {
+	input := prepared.Address
+
+	params := &uspsinternal.GetAddressParams{
+		StreetAddress: input.StreetAddress,
+		State:         input.State,
+	}
+
+	if input.SecondaryAddress != "" {
+		params.SecondaryAddress = &input.SecondaryAddress
+	}
+	if input.City != "" {
+		params.City = &input.City
+	}
+	if input.ZIPCode != "" {
+		params.ZIPCode = &input.ZIPCode
+	}
+	if input.ZIPPlus4 != "" {
+		params.ZIPPlus4 = &input.ZIPPlus4
+	}
+	if input.Firm != "" {
+		params.Firm = &input.Firm
+	}
+	if input.Urbanization != "" {
+		params.Urbanization = &input.Urbanization
 	}
 
 	c.log.Debugf("Calling USPS API with params:\n")
@@ -146,32 +195,168 @@ func (c *Client) ValidateAddress(ctx context.Context, address *Address) ([]Valid
 
 	// Handle error responses
 	if resp.StatusCode() != http.StatusOK {
-		if resp.JSON400 != nil {
-			return nil, convertError(resp.JSON400)
-		}
-		if resp.JSON401 != nil {
-			return nil, convertError(resp.JSON401)
-		}
-		if resp.JSON403 != nil {
-			return nil, convertError(resp.JSON403)
+		err := convertErrorResponse(resp.HTTPResponse, resp.JSON400, resp.JSON401, resp.JSON403, resp.JSON404, resp.JSON429, resp.JSON503)
+		return nil, ambiguousAddressError(err, input)
+	}
+
+	// Convert response to our types
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected empty response")
+	}
+
+	result := convertResponse(resp.JSON200)
+	describeCodes(&result, c.config.Locale, c.config.MessageOverride)
+	result.Input = input
+	result.Transliterations = prepared.Transliterations
+	result.SecondarySplit = prepared.SecondarySplit
+	result.Military = prepared.Military || result.Address.IsMilitary()
+
+	// Several urbanizations in Puerto Rico often share the same street names
+	if prepared.PuertoRico && input.Urbanization == "" && hasCorrection(result, "22") {
+		result.Warnings = append(result.Warnings, urbanizationWarning)
+	}
+
+	return &result, nil
+}
+
+// LookupCityState returns the city and state for a 5-digit ZIP code
+func (c *Client) LookupCityState(ctx context.Context, zip string) (*CityState, error) {
+	if c.config.StrictASCII {
+		if err := checkASCII(Address{ZIPCode: zip}); err != nil {
+			return nil, err
 		}
-		if resp.JSON404 != nil {
-			return nil, convertError(resp.JSON404)
+	}
+
+	// Full-width digits ("８０３０２") are common with some input methods
+	zip, _ = transliterateValue(FieldZIPCode, zip)
+
+	if !zipCodePattern.MatchString(zip) {
+		return nil, invalidInputf("5 digit ZIP code is required")
+	}
+
+	params := &uspsinternal.GetCityStateParams{
+		ZIPCode: zip,
+	}
+
+	c.log.Debugf("Calling USPS city-state API with ZIPCode: %q\n", params.ZIPCode)
+
+	// Call USPS API
+	resp, err := c.client.GetCityStateWithResponse(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("USPS API request failed: %w", err)
+	}
+
+	c.log.Debugf("USPS API Response Status: %d\n", resp.StatusCode())
+	if resp.Body != nil {
+		c.log.Debugf("USPS API Response Body: %s\n", string(resp.Body))
+	}
+
+	// Handle error responses
+	if resp.StatusCode() != http.StatusOK {
+		// The spec does not document a 404 for this endpoint, so it isn't parsed for us
+		var json404 *uspsinternal.ErrorMessage
+		if resp.StatusCode() == http.StatusNotFound {
+			json404 = decodeErrorMessage(resp.Body)
 		}
-		if resp.JSON429 != nil {
-			return nil, convertError(resp.JSON429)
+		return nil, convertErrorResponse(resp.HTTPResponse, resp.JSON400, resp.JSON401, resp.JSON403, json404, resp.JSON429, resp.JSON503)
+	}
+
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected empty response")
+	}
+
+	result := convertCityState(resp.JSON200)
+	return &result, nil
+}
+
+// LookupZIPCode returns the address with its ZIP code and ZIP+4 filled in
+// The street address, city and state are required
+func (c *Client) LookupZIPCode(ctx context.Context, address *Address) (*Address, error) {
+	if address == nil {
+		return nil, invalidInputf("address cannot be nil")
+	}
+
+	// Clean up the input before checking it
+	prepared, err := c.prepare(*address)
+	if err != nil {
+		return nil, err
+	}
+	input := prepared.Address
+
+	// Check everything USPS would reject before spending a request
+	if errs := checkConstraints(input, zipCodeConstraints); len(errs) > 0 {
+		return nil, errs
+	}
+
+	if prepared.Military {
+		if err := checkMilitary(input); err != nil {
+			return nil, err
 		}
-		if resp.JSON503 != nil {
-			return nil, convertError(resp.JSON503)
+	}
+
+	params := &uspsinternal.GetZIPCodeParams{
+		StreetAddress: input.StreetAddress,
+		City:          input.City,
+		State:         input.State,
+	}
+
+	if input.SecondaryAddress != "" {
+		params.SecondaryAddress = &input.SecondaryAddress
+	}
+	if input.ZIPCode != "" {
+		params.ZIPCode = &input.ZIPCode
+	}
+	if input.ZIPPlus4 != "" {
+		params.ZIPPlus4 = &input.ZIPPlus4
+	}
+	if input.Firm != "" {
+		params.Firm = &input.Firm
+	}
+
+	c.log.Debugf("Calling USPS zipcode API with params:\n")
+	c.log.Debugf("  StreetAddress: %q\n", params.StreetAddress)
+	c.log.Debugf("  City: %q\n", params.City)
+	c.log.Debugf("  State: %q\n", params.State)
+
+	// Call USPS API
+	resp, err := c.client.GetZIPCodeWithResponse(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("USPS API request failed: %w", err)
+	}
+
+	c.log.Debugf("USPS API Response Status: %d\n", resp.StatusCode())
+	if resp.Body != nil {
+		c.log.Debugf("USPS API Response Body: %s\n", string(resp.Body))
+	}
+
+	// Handle error responses
+	if resp.StatusCode() != http.StatusOK {
+		// The spec does not document a 404 for this endpoint, so it isn't parsed for us
+		var json404 *uspsinternal.ErrorMessage
+		if resp.StatusCode() == http.StatusNotFound {
+			json404 = decodeErrorMessage(resp.Body)
 		}
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		err := convertErrorResponse(resp.HTTPResponse, resp.JSON400, resp.JSON401, resp.JSON403, json404, resp.JSON429, resp.JSON503)
+		return nil, ambiguousAddressError(err, input)
 	}
 
-	// Convert response to our types
-	if resp.JSON200 == nil {
+	if resp.JSON200 == nil || resp.JSON200.Address == nil {
 		return nil, fmt.Errorf("unexpected empty response")
 	}
 
-	result := convertResponse(resp.JSON200)
-	return []ValidationResult{result}, nil
+	result := convertAddress(resp.JSON200.Address, resp.JSON200.Firm)
+	return &result, nil
+}
+
+// prepare cleans up address input before a request
+// In strict mode, input with non-ASCII characters is rejected instead of transliterated
+func (c *Client) prepare(address Address) (preparedAddress, error) {
+	check := checkTransliterable
+	if c.config.StrictASCII {
+		check = checkASCII
+	}
+	if err := check(address); err != nil {
+		return preparedAddress{}, err
+	}
+	return prepareAddress(address), nil
 }
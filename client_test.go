@@ -0,0 +1,91 @@
+package uspsaddr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// notFoundResponse and ambiguousResponse are USPS 404 bodies
+const (
+	notFoundResponse  = `{"apiVersion": "v3", "error": {"code": "404", "message": "Address Not Found."}}`
+	ambiguousResponse = `{"apiVersion": "v3", "error": {"code": "404", "message": "More than one address was found matching the information entered."}}`
+)
+
+// newTestClient returns a client for a fake USPS server that answers /address requests with
+// respond, given the request's query parameters
+func newTestClient(t *testing.T, config Config, respond func(query url.Values) (int, string)) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			fmt.Fprint(w, `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`)
+		case "/address":
+			status, body := respond(r.URL.Query())
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, notFoundResponse)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config.ClientID = "id"
+	config.ClientSecret = "secret"
+	config.ServerURL = server.URL
+	config.TokenURL = server.URL + "/token"
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+// confirmedResponse is a USPS response for a DPV confirmed street address in Boulder
+func confirmedResponse(street string) string {
+	return fmt.Sprintf(`{"address": {"streetAddress": %q, "city": "BOULDER", "state": "CO", "ZIPCode": "80302"}, "additionalInfo": {"DPVConfirmation": "Y"}}`, street)
+}
+
+func TestValidateAddressCandidates(t *testing.T) {
+	client := newTestClient(t, Config{CandidateBudget: 8}, func(query url.Values) (int, string) {
+		// Only the street without the unit exists, and with the unit it is ambiguous
+		switch {
+		case query.Get("streetAddress") == "100 Main St" && query.Get("secondaryAddress") == "":
+			return http.StatusOK, confirmedResponse("100 MAIN ST")
+		case query.Get("streetAddress") == "100 Main St":
+			return http.StatusNotFound, ambiguousResponse
+		}
+		return http.StatusNotFound, notFoundResponse
+	})
+
+	results, err := client.ValidateAddress(context.Background(), &Address{StreetAddress: "100 Main St Apt 5", City: "Boulder", State: "CO"})
+
+	var ambiguous *AmbiguousAddressError
+	if !errors.Is(err, ErrMultipleAddresses) || !errors.As(err, &ambiguous) {
+		t.Fatalf("ValidateAddress() error = %v, want an *AmbiguousAddressError", err)
+	}
+	if len(ambiguous.Hints) == 0 {
+		t.Errorf("AmbiguousAddressError.Hints is empty")
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("ValidateAddress() returned %d results, want 1 alternative", len(results))
+	}
+	result := results[0]
+	if result.Candidate == nil || result.Candidate.Kind != CandidateSecondary || result.Candidate.Rank != 1 {
+		t.Errorf("Candidate = %+v, want the dropped secondary ranked 1", result.Candidate)
+	}
+	if result.SecondarySplit != nil {
+		t.Errorf("SecondarySplit = %+v, want nil for a variant", result.SecondarySplit)
+	}
+	if result.Address.StreetAddress != "100 MAIN ST" {
+		t.Errorf("StreetAddress = %q, want 100 MAIN ST", result.Address.StreetAddress)
+	}
+}
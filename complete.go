@@ -0,0 +1,102 @@
+package uspsaddr
+
+import "context"
+
+// Endpoint identifies a USPS Addresses API endpoint
+type Endpoint string
+
+// USPS Addresses API endpoints
+const (
+	EndpointAddress   Endpoint = "/address"
+	EndpointCityState Endpoint = "/city-state"
+	EndpointZIPCode   Endpoint = "/zipcode"
+)
+
+// InferredField records an address field that Complete filled in
+type InferredField struct {
+	Field Field
+	Value string
+
+	// Source is the USPS endpoint the value came from
+	Source Endpoint
+}
+
+// CompletionResult contains the results of Complete
+type CompletionResult struct {
+	// The input with any missing fields filled in, as sent to ValidateAddress
+	Address Address
+
+	// Fields that were missing from the input and filled in from USPS
+	Inferred []InferredField
+
+	// Characters in the input that were replaced with ASCII
+	Transliterations []Transliteration
+
+	// The validation results for the completed address
+	Results []ValidationResult
+}
+
+// Complete fills in missing address fields and then validates the address
+// A missing city or state is looked up from the ZIP code, and a missing ZIP code is
+// looked up from the street address, city and state
+func (c *Client) Complete(ctx context.Context, address *Address) (*CompletionResult, error) {
+	if address == nil {
+		return nil, invalidInputf("address cannot be nil")
+	}
+
+	check := checkTransliterable
+	if c.config.StrictASCII {
+		check = checkASCII
+	}
+	if err := check(*address); err != nil {
+		return nil, err
+	}
+
+	input, transliterations := Transliterate(*address)
+	input = NormalizeAddress(input)
+	result := &CompletionResult{
+		Transliterations: transliterations,
+	}
+
+	infer := func(field Field, value *string, inferred string, source Endpoint) {
+		if *value != "" || inferred == "" {
+			return
+		}
+		*value = inferred
+		result.Inferred = append(result.Inferred, InferredField{
+			Field:  field,
+			Value:  inferred,
+			Source: source,
+		})
+	}
+
+	// Fill in the city and state from the ZIP code
+	if input.ZIPCode != "" && (input.City == "" || input.State == "") {
+		cs, err := c.LookupCityState(ctx, input.ZIPCode)
+		if err != nil {
+			return nil, err
+		}
+		infer(FieldCity, &input.City, cs.City, EndpointCityState)
+		infer(FieldState, &input.State, cs.State, EndpointCityState)
+	}
+
+	// Fill in the ZIP code from the city and state
+	if input.ZIPCode == "" && input.StreetAddress != "" && input.City != "" && input.State != "" {
+		zip, err := c.LookupZIPCode(ctx, &input)
+		if err != nil {
+			return nil, err
+		}
+		infer(FieldZIPCode, &input.ZIPCode, zip.ZIPCode, EndpointZIPCode)
+		infer(FieldZIPPlus4, &input.ZIPPlus4, zip.ZIPPlus4, EndpointZIPCode)
+	}
+
+	results, err := c.ValidateAddress(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Address = input
+	result.Results = results
+
+	return result, nil
+}
@@ -19,6 +19,32 @@ type Config struct {
 	TokenURL string
 
 	LogLevel string
+
+	// StrictASCII rejects addresses containing non-ASCII characters with a *NonASCIIError
+	// By default they are transliterated ("Peñasco" becomes "Penasco") and the changes are
+	// recorded in ValidationResult.Transliterations
+	StrictASCII bool
+
+	// CheckZIPState makes ValidateAddress check that the ZIP code belongs to the state before
+	// making a request, returning a *ZIPStateMismatchError if it doesn't (see CheckZIPState)
+	CheckZIPState bool
+
+	// CandidateBudget turns on the search for alternatives when an address is ambiguous or not
+	// found: ValidateAddress tries variants of the input, such as adding a directional or changing
+	// the suffix, using at most this many extra requests. 0 turns it off (see Candidate)
+	CandidateBudget int
+
+	// Locale is the language of Correction and Match user messages, such as "en" or "es"
+	// Defaults to English, which is also used for codes without a message in the locale
+	Locale string
+
+	// MessageOverride, if set, is called for every correction and match code and can replace
+	// the catalog's user message with different wording
+	MessageOverride MessageOverride
+
+	// Policy, if set, is evaluated against every result of ValidateAddress (see Policy)
+	// Rejected results are dropped, and if none are left a *PolicyError is returned
+	Policy *Policy
 }
 
 // Validate checks if the config is valid
@@ -35,6 +61,14 @@ func (c *Config) Validate() error {
 			Detail: "ClientSecret is required",
 		}
 	}
+	if c.Policy != nil {
+		if err := c.Policy.Validate(); err != nil {
+			return &Error{
+				Title:  "Invalid configuration",
+				Detail: err.Error(),
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,90 @@
+// Code generated by internal/genconstraints from usps-addresses-v3r2_2.yaml; DO NOT EDIT.
+
+package uspsaddr
+
+import "regexp"
+
+// addressConstraints are the query parameter constraints of the get-address operation
+var addressConstraints = []fieldConstraint{
+	{
+		Field:     FieldFirm,
+		Parameter: "firm",
+		MaxLength: 50,
+	},
+	{
+		Field:     FieldStreetAddress,
+		Parameter: "streetAddress",
+		Required:  true,
+	},
+	{
+		Field:     FieldSecondaryAddress,
+		Parameter: "secondaryAddress",
+	},
+	{
+		Field:     FieldCity,
+		Parameter: "city",
+	},
+	{
+		Field:     FieldState,
+		Parameter: "state",
+		Required:  true,
+		MinLength: 2,
+		MaxLength: 2,
+		Pattern:   regexp.MustCompile(`^(AA|AE|AL|AK|AP|AS|AZ|AR|CA|CO|CT|DE|DC|FM|FL|GA|GU|HI|ID|IL|IN|IA|KS|KY|LA|ME|MH|MD|MA|MI|MN|MS|MO|MP|MT|NE|NV|NH|NJ|NM|NY|NC|ND|OH|OK|OR|PW|PA|PR|RI|SC|SD|TN|TX|UT|VT|VI|VA|WA|WV|WI|WY)$`),
+	},
+	{
+		Field:     FieldUrbanization,
+		Parameter: "urbanization",
+	},
+	{
+		Field:     FieldZIPCode,
+		Parameter: "ZIPCode",
+		Pattern:   regexp.MustCompile(`^\d{5}$`),
+	},
+	{
+		Field:     FieldZIPPlus4,
+		Parameter: "ZIPPlus4",
+		Pattern:   regexp.MustCompile(`^\d{4}$`),
+	},
+}
+
+// zipCodeConstraints are the query parameter constraints of the get-ZIPCode operation
+var zipCodeConstraints = []fieldConstraint{
+	{
+		Field:     FieldFirm,
+		Parameter: "firm",
+		MaxLength: 50,
+	},
+	{
+		Field:     FieldStreetAddress,
+		Parameter: "streetAddress",
+		Required:  true,
+	},
+	{
+		Field:     FieldSecondaryAddress,
+		Parameter: "secondaryAddress",
+	},
+	{
+		Field:     FieldCity,
+		Parameter: "city",
+		Required:  true,
+	},
+	{
+		Field:     FieldState,
+		Parameter: "state",
+		Required:  true,
+		MinLength: 2,
+		MaxLength: 2,
+		Pattern:   regexp.MustCompile(`^(AA|AE|AL|AK|AP|AS|AZ|AR|CA|CO|CT|DE|DC|FM|FL|GA|GU|HI|ID|IL|IN|IA|KS|KY|LA|ME|MH|MD|MA|MI|MN|MS|MO|MP|MT|NE|NV|NH|NJ|NM|NY|NC|ND|OH|OK|OR|PW|PA|PR|RI|SC|SD|TN|TX|UT|VT|VI|VA|WA|WV|WI|WY)$`),
+	},
+	{
+		Field:     FieldZIPCode,
+		Parameter: "ZIPCode",
+		Pattern:   regexp.MustCompile(`^\d{5}$`),
+	},
+	{
+		Field:     FieldZIPPlus4,
+		Parameter: "ZIPPlus4",
+		Pattern:   regexp.MustCompile(`^\d{4}$`),
+	},
+}
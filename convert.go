@@ -1,6 +1,11 @@
 package uspsaddr
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/tadhunt/uspsaddr/uspsinternal"
 )
 
@@ -13,15 +18,6 @@ func convertResponse(resp *uspsinternal.AddressResponse) ValidationResult {
 		result.Address = convertAddress(resp.Address, resp.Firm)
 	}
 
-	// Get DPV confirmation for generating user messages
-	dpvConfirmation := ""
-	if resp.AdditionalInfo != nil && resp.AdditionalInfo.DPVConfirmation != nil {
-		dpvConfirmation = string(*resp.AdditionalInfo.DPVConfirmation)
-	}
-
-	// Check if secondary address is present in response
-	hasSecondaryAddress := resp.Address != nil && resp.Address.SecondaryAddress != nil && *resp.Address.SecondaryAddress != ""
-
 	// Convert corrections
 	if resp.Corrections != nil {
 		result.Corrections = make([]Correction, 0, len(*resp.Corrections))
@@ -30,11 +26,9 @@ func convertResponse(resp *uspsinternal.AddressResponse) ValidationResult {
 			text := stringValue(c.Text)
 			// Only add non-empty corrections
 			if code != "" || text != "" {
-				userMessage := generateUserMessage(code, text, dpvConfirmation, hasSecondaryAddress)
 				result.Corrections = append(result.Corrections, Correction{
-					Code:        code,
-					Text:        text,
-					UserMessage: userMessage,
+					Code: code,
+					Text: text,
 				})
 			}
 		}
@@ -66,6 +60,12 @@ func convertResponse(resp *uspsinternal.AddressResponse) ValidationResult {
 		result.AdditionalInfo = convertAdditionalInfo(resp.AdditionalInfo)
 	}
 
+	carrierRoute := ""
+	if result.AdditionalInfo != nil {
+		carrierRoute = result.AdditionalInfo.CarrierRoute
+	}
+	result.AddressType = classifyAddress(result.Address, carrierRoute)
+
 	return result
 }
 
@@ -129,28 +129,74 @@ func convertAdditionalInfo(info *uspsinternal.AddressAdditionalInfo) *Additional
 	}
 
 	if info.DPVConfirmation != nil {
-		result.DPVConfirmation = string(*info.DPVConfirmation)
+		result.DPVConfirmation = DPV(*info.DPVConfirmation)
 	}
 
 	if info.DPVCMRA != nil {
-		result.DPVCMRA = string(*info.DPVCMRA)
+		result.DPVCMRA = Flag(*info.DPVCMRA)
 	}
 
 	if info.Business != nil {
-		result.Business = string(*info.Business)
+		result.Business = Flag(*info.Business)
 	}
 
 	if info.CentralDeliveryPoint != nil {
-		result.CentralDeliveryPoint = string(*info.CentralDeliveryPoint)
+		result.CentralDeliveryPoint = Flag(*info.CentralDeliveryPoint)
 	}
 
 	if info.Vacant != nil {
-		result.Vacant = string(*info.Vacant)
+		result.Vacant = Flag(*info.Vacant)
+	}
+
+	return result
+}
+
+// convertCityState converts a USPS city-state response to our CityState type
+func convertCityState(resp *uspsinternal.CityStateResponse) CityState {
+	return CityState{
+		City:    stringValue(resp.City),
+		State:   stringValue(resp.State),
+		ZIPCode: stringValue(resp.ZIPCode),
+	}
+}
+
+// convertErrorResponse converts an error response to our Error type, using the first non-nil
+// USPS error body along with the HTTP status and headers
+// USPS doesn't always send a body we can parse, so the HTTP status alone is enough
+func convertErrorResponse(httpResp *http.Response, errResps ...*uspsinternal.ErrorMessage) error {
+	result := &Error{}
+	for _, errResp := range errResps {
+		if errResp != nil {
+			result = convertError(errResp)
+			break
+		}
+	}
+
+	if httpResp != nil {
+		result.HTTPStatus = httpResp.StatusCode
+		result.RetryAfter = parseRetryAfter(httpResp.Header.Get("Retry-After"))
+	}
+
+	if result.Title == "" && result.Detail == "" {
+		result.Title = http.StatusText(result.HTTPStatus)
+		result.Detail = fmt.Sprintf("unexpected status code: %d", result.HTTPStatus)
 	}
 
 	return result
 }
 
+// decodeErrorMessage parses a USPS error body for status codes the generated client doesn't handle
+func decodeErrorMessage(body []byte) *uspsinternal.ErrorMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var errResp uspsinternal.ErrorMessage
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return nil
+	}
+	return &errResp
+}
+
 // convertError converts a USPS error response to our Error type
 func convertError(errResp *uspsinternal.ErrorMessage) *Error {
 	if errResp == nil || errResp.Error == nil {
@@ -158,11 +204,12 @@ func convertError(errResp *uspsinternal.ErrorMessage) *Error {
 	}
 
 	e := errResp.Error
-	result := &Error{}
+	result := &Error{
+		APIVersion: stringValue(errResp.ApiVersion),
+	}
 
 	if e.Code != nil {
 		result.Code = *e.Code
-		result.Status = *e.Code // Use code as status since no separate status field
 	}
 
 	if e.Message != nil {
@@ -170,30 +217,65 @@ func convertError(errResp *uspsinternal.ErrorMessage) *Error {
 		result.Title = *e.Message
 	}
 
-	// Check if there are detailed errors
-	if e.Errors != nil && len(*e.Errors) > 0 {
-		// Use first error for more details
-		firstErr := (*e.Errors)[0]
-		if firstErr.Detail != nil {
-			result.Detail = *firstErr.Detail
+	// Keep every detailed error; the first one describes the error as a whole
+	if e.Errors != nil {
+		for _, item := range *e.Errors {
+			result.Errors = append(result.Errors, convertErrorDetail(item))
 		}
-		if firstErr.Title != nil {
-			result.Title = *firstErr.Title
+	}
+	if len(result.Errors) > 0 {
+		first := result.Errors[0]
+		if first.Detail != "" {
+			result.Detail = first.Detail
 		}
-		if firstErr.Source != nil {
-			result.Source = &ErrorSource{}
-			if firstErr.Source.Parameter != nil {
-				result.Source.Parameter = *firstErr.Source.Parameter
-			}
-			if firstErr.Source.Example != nil {
-				result.Source.Example = *firstErr.Source.Example
-			}
+		if first.Title != "" {
+			result.Title = first.Title
 		}
+		result.Source = first.Source
 	}
 
 	return result
 }
 
+// convertErrorDetail converts one of the detailed errors in a USPS error response
+func convertErrorDetail(item uspsinternal.ErrorMessage_Error_Errors_Item) ErrorDetail {
+	detail := ErrorDetail{
+		Status: stringValue(item.Status),
+		Code:   stringValue(item.Code),
+		Title:  stringValue(item.Title),
+		Detail: stringValue(item.Detail),
+	}
+
+	if item.Source != nil {
+		parameter := stringValue(item.Source.Parameter)
+		detail.Source = &ErrorSource{
+			Parameter: parameter,
+			Field:     parameterField(parameter),
+			Example:   stringValue(item.Source.Example),
+		}
+	}
+
+	return detail
+}
+
+// parameterFields maps USPS request parameter names, lower-cased, to Address fields
+var parameterFields = func() map[string]Field {
+	fields := map[string]Field{}
+	for _, c := range addressConstraints {
+		fields[strings.ToLower(c.Parameter)] = c.Field
+	}
+	return fields
+}()
+
+// parameterField returns the Address field for a USPS request parameter, or "" if there isn't one
+// The parameter may be qualified ("query.streetAddress") and its case isn't significant
+func parameterField(parameter string) Field {
+	if i := strings.LastIndexAny(parameter, "./"); i >= 0 {
+		parameter = parameter[i+1:]
+	}
+	return parameterFields[strings.ToLower(strings.TrimSpace(parameter))]
+}
+
 // stringValue safely dereferences a string pointer
 func stringValue(s *string) string {
 	if s == nil {
@@ -201,20 +283,3 @@ func stringValue(s *string) string {
 	}
 	return *s
 }
-
-// generateUserMessage creates a user-friendly message based on correction code and DPV confirmation
-func generateUserMessage(code, text, dpvConfirmation string, hasSecondaryAddress bool) string {
-	// Handle correction code 32 (more information needed)
-	if code == "32" {
-		if dpvConfirmation == "D" {
-			// D = Missing secondary information
-			return text // Use the original USPS message
-		} else if dpvConfirmation == "S" && hasSecondaryAddress {
-			// S = Secondary information present but not confirmed
-			return "Unable to validate the secondary address (suite, apt number, etc). Please double check what you entered."
-		}
-	}
-
-	// For all other cases, use the original USPS text
-	return text
-}
@@ -0,0 +1,288 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeKind is the kind of change USPS made to a field of the input
+type ChangeKind int
+
+const (
+	// ChangeFormatted means only case, punctuation or abbreviations changed ("Street" to "ST")
+	ChangeFormatted ChangeKind = iota
+
+	// ChangeAdded means a field that was empty was filled in
+	ChangeAdded
+
+	// ChangeRemoved means a field that was given was dropped
+	ChangeRemoved
+
+	// ChangeCorrected means the value was replaced with a different one
+	ChangeCorrected
+
+	// ChangeCityPreferredName means the city was replaced by a different name, such as a
+	// neighborhood replaced by the USPS preferred city for the ZIP code
+	// A spelling fix ("Bouldr" to "BOULDER") is ChangeCorrected
+	ChangeCityPreferredName
+
+	// ChangeSuffixStandardized means the street suffix was abbreviated ("Avenue" to "AVE")
+	ChangeSuffixStandardized
+
+	// ChangeSuffixAdded means a street suffix was added
+	ChangeSuffixAdded
+
+	// ChangeSuffixChanged means the street suffix was replaced with a different one ("ST" to "AVE")
+	ChangeSuffixChanged
+
+	// ChangeDirectionalAdded means a directional was added ("MAIN ST" to "N MAIN ST")
+	ChangeDirectionalAdded
+
+	// ChangeDirectionalChanged means the directional was replaced with a different one
+	ChangeDirectionalChanged
+
+	// ChangeZIPPlus4Added means the ZIP+4 was filled in
+	ChangeZIPPlus4Added
+
+	// ChangeSecondaryReformatted means the secondary unit was written differently but has the
+	// same number ("#15" to "APT 15"), or was moved off the street line
+	ChangeSecondaryReformatted
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeFormatted:
+		return "formatted"
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeCorrected:
+		return "corrected"
+	case ChangeCityPreferredName:
+		return "city preferred name"
+	case ChangeSuffixStandardized:
+		return "suffix standardized"
+	case ChangeSuffixAdded:
+		return "suffix added"
+	case ChangeSuffixChanged:
+		return "suffix changed"
+	case ChangeDirectionalAdded:
+		return "directional added"
+	case ChangeDirectionalChanged:
+		return "directional changed"
+	case ChangeZIPPlus4Added:
+		return "ZIP+4 added"
+	case ChangeSecondaryReformatted:
+		return "secondary reformatted"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Impact says whether a change alters the address or only how it is written
+type Impact int
+
+const (
+	// ImpactCosmetic changes only how the address is written, or add detail that doesn't change
+	// where it is (the ZIP+4)
+	ImpactCosmetic Impact = iota
+
+	// ImpactSubstantive changes are worth confirming with the user
+	ImpactSubstantive
+)
+
+func (i Impact) String() string {
+	switch i {
+	case ImpactCosmetic:
+		return "cosmetic"
+	case ImpactSubstantive:
+		return "substantive"
+	}
+	return fmt.Sprintf("Impact(%d)", int(i))
+}
+
+// Change is one difference between the input and the canonical address
+type Change struct {
+	Field  Field
+	Kind   ChangeKind
+	Impact Impact
+
+	// The field as given in the input, and as USPS returned it
+	From string
+	To   string
+}
+
+// diffFields are the fields compared by Diff; the abbreviation fields only come from USPS
+var diffFields = []Field{
+	FieldFirm,
+	FieldStreetAddress,
+	FieldSecondaryAddress,
+	FieldCity,
+	FieldState,
+	FieldZIPCode,
+	FieldZIPPlus4,
+	FieldUrbanization,
+}
+
+// Diff lists the changes USPS made to the input, for showing the user a "we updated your
+// address" summary
+// Both sides are compared in their standardized form (see Standardize), so differences in case,
+// punctuation and abbreviations are reported as cosmetic
+func Diff(input Address, result ValidationResult) []Change {
+	standardized := Standardize(input)
+	output := result.Address
+
+	var changes []Change
+	for _, field := range diffFields {
+		from, std, to := input.Get(field), standardized.Get(field), output.Get(field)
+		if from == to {
+			continue
+		}
+
+		change := func(kind ChangeKind, impact Impact) {
+			changes = append(changes, Change{Field: field, Kind: kind, Impact: impact, From: from, To: to})
+		}
+
+		switch {
+		case std == to:
+			switch {
+			case field == FieldStreetAddress && suffixStandardized(input.StreetAddress, to):
+				change(ChangeSuffixStandardized, ImpactCosmetic)
+			case field == FieldSecondaryAddress:
+				change(ChangeSecondaryReformatted, ImpactCosmetic)
+			default:
+				change(ChangeFormatted, ImpactCosmetic)
+			}
+
+		case to == "":
+			change(ChangeRemoved, ImpactSubstantive)
+
+		case std == "" && field == FieldZIPPlus4:
+			change(ChangeZIPPlus4Added, ImpactCosmetic)
+
+		case std == "":
+			change(ChangeAdded, ImpactSubstantive)
+
+		case field == FieldCity && std == strings.ToUpper(output.CityAbbreviation):
+			change(ChangeFormatted, ImpactCosmetic)
+
+		case field == FieldCity && isPreferredCityName(std, to):
+			change(ChangeCityPreferredName, ImpactSubstantive)
+
+		case field == FieldSecondaryAddress && secondaryNumber(std) == secondaryNumber(to):
+			change(ChangeSecondaryReformatted, ImpactCosmetic)
+
+		case field == FieldStreetAddress:
+			for _, kind := range streetChanges(std, to) {
+				change(kind, ImpactSubstantive)
+			}
+			if suffixStandardized(input.StreetAddress, to) {
+				change(ChangeSuffixStandardized, ImpactCosmetic)
+			}
+
+		default:
+			change(ChangeCorrected, ImpactSubstantive)
+		}
+	}
+
+	return changes
+}
+
+// streetLine is a street address split into its parts, in the positions standardizeStreet uses
+type streetLine struct {
+	Number      string
+	Directional string
+	Name        string
+	Suffix      string
+}
+
+// parseStreet splits a standardized street line into its parts
+// A pre or post-directional both end up in Directional
+func parseStreet(street string) streetLine {
+	words := strings.Fields(street)
+
+	var line streetLine
+	if len(words) > 1 && startsWithDigit(words) {
+		line.Number = words[0]
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return line
+	}
+
+	if _, ok := directional(words[len(words)-1]); ok && len(words) > 1 {
+		line.Directional = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	if _, ok := streetSuffix(words[len(words)-1]); ok && len(words) > 1 {
+		line.Suffix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	if _, ok := directional(words[0]); ok && len(words) > 1 && line.Directional == "" {
+		line.Directional = words[0]
+		words = words[1:]
+	}
+
+	line.Name = strings.Join(words, " ")
+	return line
+}
+
+// streetChanges lists what changed between two standardized street lines
+func streetChanges(from, to string) []ChangeKind {
+	a, b := parseStreet(from), parseStreet(to)
+
+	var kinds []ChangeKind
+	switch {
+	case a.Directional == b.Directional:
+	case a.Directional == "":
+		kinds = append(kinds, ChangeDirectionalAdded)
+	case b.Directional != "":
+		kinds = append(kinds, ChangeDirectionalChanged)
+	}
+
+	switch {
+	case a.Suffix == b.Suffix:
+	case a.Suffix == "":
+		kinds = append(kinds, ChangeSuffixAdded)
+	case b.Suffix != "":
+		kinds = append(kinds, ChangeSuffixChanged)
+	}
+
+	// Anything else, such as a different house number or street name, or a dropped directional or suffix
+	dropped := (a.Directional != "" && b.Directional == "") || (a.Suffix != "" && b.Suffix == "")
+	if len(kinds) == 0 || dropped || a.Number != b.Number || a.Name != b.Name {
+		kinds = append(kinds, ChangeCorrected)
+	}
+
+	return kinds
+}
+
+// suffixStandardized reports whether the suffix of the street line as given was spelled out or
+// abbreviated differently from the canonical street ("Avenue" or "Av" rather than "AVE")
+func suffixStandardized(given, canonical string) bool {
+	words, _ := splitSecondaryTokens(strings.Fields(strings.ToUpper(normalizeLine(given))))
+	suffix := parseStreet(strings.Join(words, " ")).Suffix
+	if suffix == "" {
+		return false
+	}
+	abbr, _ := streetSuffix(suffix)
+	return suffix != abbr && abbr == parseStreet(canonical).Suffix
+}
+
+// isPreferredCityName reports whether a city was replaced by a different name, rather than
+// having its spelling fixed
+func isPreferredCityName(from, to string) bool {
+	return editDistance(from, to) > max(1, len(to)/4)
+}
+
+// secondaryNumber returns the unit number of a standardized secondary address ("APT 15" is "15")
+func secondaryNumber(secondary string) string {
+	words := strings.Fields(secondary)
+	if len(words) == 0 {
+		return ""
+	}
+	if _, ok := lookupSecondaryUnit(words[0]); ok || words[0] == "#" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,178 @@
+package uspsaddr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type change struct {
+		Field  Field
+		Kind   ChangeKind
+		Impact Impact
+	}
+
+	tests := []struct {
+		name   string
+		input  Address
+		output Address
+		want   []change
+	}{
+		{
+			name:   "unchanged",
+			input:  Address{StreetAddress: "123 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80302"},
+			output: Address{StreetAddress: "123 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80302"},
+		},
+		{
+			name:   "case and state name only",
+			input:  Address{StreetAddress: "123 Main St", City: "Boulder", State: "Colorado", ZIPCode: "80302"},
+			output: Address{StreetAddress: "123 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80302"},
+			want: []change{
+				{FieldStreetAddress, ChangeFormatted, ImpactCosmetic},
+				{FieldCity, ChangeFormatted, ImpactCosmetic},
+				{FieldState, ChangeFormatted, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "suffix standardized",
+			input:  Address{StreetAddress: "12 Elm Avenue", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "12 ELM AVE", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeSuffixStandardized, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "suffix added to two-word street",
+			input:  Address{StreetAddress: "123 Main", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "123 MAIN ST", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeSuffixAdded, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "directional added to two-word street",
+			input:  Address{StreetAddress: "100 Broadway", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 N BROADWAY", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeDirectionalAdded, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "directional added and suffix standardized",
+			input:  Address{StreetAddress: "100 Main Street", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 N MAIN ST", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeDirectionalAdded, ImpactSubstantive},
+				{FieldStreetAddress, ChangeSuffixStandardized, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "directional and suffix changed, house number corrected",
+			input:  Address{StreetAddress: "12 N Elm Rd", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "14 S ELM ST", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeDirectionalChanged, ImpactSubstantive},
+				{FieldStreetAddress, ChangeSuffixChanged, ImpactSubstantive},
+				{FieldStreetAddress, ChangeCorrected, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "city preferred name",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "Hollywood", State: "CA", ZIPCode: "90028"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "LOS ANGELES", State: "CA", ZIPCode: "90028"},
+			want: []change{
+				{FieldCity, ChangeCityPreferredName, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "city spelling fixed",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "Bouldr", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldCity, ChangeCorrected, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "city given as its abbreviation",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "Ft Collins", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "FORT COLLINS", CityAbbreviation: "FT COLLINS", State: "CO"},
+			want: []change{
+				{FieldCity, ChangeFormatted, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "secondary moved off the street line",
+			input:  Address{StreetAddress: "100 MAIN ST APT 2", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", SecondaryAddress: "APT 2", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldStreetAddress, ChangeFormatted, ImpactCosmetic},
+				{FieldSecondaryAddress, ChangeSecondaryReformatted, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "secondary designator filled in",
+			input:  Address{StreetAddress: "100 MAIN ST", SecondaryAddress: "#5", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", SecondaryAddress: "APT 5", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldSecondaryAddress, ChangeSecondaryReformatted, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "secondary number corrected",
+			input:  Address{StreetAddress: "100 MAIN ST", SecondaryAddress: "Ste 5", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", SecondaryAddress: "STE 7", City: "BOULDER", State: "CO"},
+			want: []change{
+				{FieldSecondaryAddress, ChangeCorrected, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "ZIP code added and ZIP+4 appended",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "BOULDER", State: "CO"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80302", ZIPPlus4: "1234"},
+			want: []change{
+				{FieldZIPCode, ChangeAdded, ImpactSubstantive},
+				{FieldZIPPlus4, ChangeZIPPlus4Added, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "ZIP code corrected",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80301"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "BOULDER", State: "CO", ZIPCode: "80302"},
+			want: []change{
+				{FieldZIPCode, ChangeCorrected, ImpactSubstantive},
+			},
+		},
+		{
+			name:   "military box moved onto the unit line",
+			input:  Address{StreetAddress: "PSC 1234", SecondaryAddress: "Box 5678", City: "APO", State: "AE", ZIPCode: "09001"},
+			output: Address{StreetAddress: "PSC 1234 BOX 5678", City: "APO", State: "AE", ZIPCode: "09001"},
+			want: []change{
+				{FieldStreetAddress, ChangeFormatted, ImpactCosmetic},
+				{FieldSecondaryAddress, ChangeSecondaryReformatted, ImpactCosmetic},
+			},
+		},
+		{
+			name:   "transliterated city",
+			input:  Address{StreetAddress: "100 MAIN ST", City: "Peñasco", State: "NM"},
+			output: Address{StreetAddress: "100 MAIN ST", City: "PENASCO", State: "NM"},
+			want: []change{
+				{FieldCity, ChangeFormatted, ImpactCosmetic},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []change
+			for _, c := range Diff(tt.input, ValidationResult{Address: tt.output}) {
+				got = append(got, change{c.Field, c.Kind, c.Impact})
+				if c.From != tt.input.Get(c.Field) || c.To != tt.output.Get(c.Field) {
+					t.Errorf("%s: From/To = %q/%q, want %q/%q", c.Field, c.From, c.To, tt.input.Get(c.Field), tt.output.Get(c.Field))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Diff() = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package uspsaddr
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DPV is the Delivery Point Validation confirmation indicator, which says whether the address
+// matched a known USPS delivery point
+// Values USPS adds in the future are kept as they were sent
+type DPV string
+
+const (
+	// DPVConfirmed means the primary number and the secondary number, if any, were confirmed
+	DPVConfirmed DPV = "Y"
+
+	// DPVMissingSecondary means the primary number was confirmed but the secondary number is missing
+	DPVMissingSecondary DPV = "D"
+
+	// DPVSecondaryUnconfirmed means the primary number was confirmed but the secondary number was not
+	DPVSecondaryUnconfirmed DPV = "S"
+
+	// DPVNotConfirmed means the address didn't match a delivery point
+	DPVNotConfirmed DPV = "N"
+
+	// DPVUnknown means USPS didn't return a DPV indicator
+	DPVUnknown DPV = ""
+)
+
+// Confirmed reports whether the primary number was confirmed (Y, D or S)
+// A confirmed address isn't necessarily one USPS delivers to; see AdditionalInfo.CarrierRoute
+func (d DPV) Confirmed() bool {
+	switch d {
+	case DPVConfirmed, DPVMissingSecondary, DPVSecondaryUnconfirmed:
+		return true
+	}
+	return false
+}
+
+// MissingSecondary reports whether the address needs a secondary number (apartment, suite)
+// that wasn't given (D)
+func (d DPV) MissingSecondary() bool {
+	return d == DPVMissingSecondary
+}
+
+// SecondaryUnconfirmed reports whether the secondary number was given but didn't match (S)
+func (d DPV) SecondaryUnconfirmed() bool {
+	return d == DPVSecondaryUnconfirmed
+}
+
+// Flag is a Y/N indicator from USPS that may also be missing
+// Values other than Y and N are kept as they were sent, and count as unknown
+type Flag string
+
+const (
+	// FlagYes is "Y"
+	FlagYes Flag = "Y"
+
+	// FlagNo is "N"
+	FlagNo Flag = "N"
+
+	// FlagUnknown means USPS didn't return the indicator
+	FlagUnknown Flag = ""
+)
+
+// Yes reports whether the flag is set
+func (f Flag) Yes() bool {
+	return f == FlagYes
+}
+
+// No reports whether the flag is explicitly not set
+func (f Flag) No() bool {
+	return f == FlagNo
+}
+
+// Unknown reports whether the flag is missing or not one of Y and N
+func (f Flag) Unknown() bool {
+	return f != FlagYes && f != FlagNo
+}
+
+// UnmarshalYAML reads a flag written as Y or N, or as a boolean ("true", "yes", "false", "no"),
+// so `vacant: true` in a Policy means the same as `vacant: "Y"`
+// This applies wherever a Flag is decoded from YAML, including an AdditionalInfo; other values
+// are kept as written
+func (f *Flag) UnmarshalYAML(node *yaml.Node) error {
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	switch strings.ToLower(value) {
+	case "y", "yes", "true":
+		*f = FlagYes
+	case "n", "no", "false":
+		*f = FlagNo
+	default:
+		*f = Flag(value)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package uspsaddr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for use with errors.Is
+// Errors returned by USPS (*Error) match the sentinel for their HTTP status, and
+// input rejected locally before a request is made matches ErrInvalidInput
+var (
+	// ErrInvalidInput means the address was rejected, locally or by USPS (HTTP 400)
+	ErrInvalidInput = errors.New("invalid address input")
+
+	// ErrAddressNotFound means USPS couldn't match the address (HTTP 404)
+	ErrAddressNotFound = errors.New("address not found")
+
+	// ErrMultipleAddresses means the address matched more than one address (HTTP 404)
+	ErrMultipleAddresses = errors.New("multiple addresses found")
+
+	// ErrUnauthorized means the access token was missing or rejected (HTTP 401)
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden means the credentials don't have access to the Addresses API (HTTP 403)
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrRateLimited means too many requests were made; see Error.RetryAfter (HTTP 429)
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrServiceUnavailable means USPS is temporarily unavailable; see Error.RetryAfter (HTTP 503)
+	ErrServiceUnavailable = errors.New("USPS service unavailable")
+
+	// ErrRejectedByPolicy means Config.Policy rejected every result; see PolicyError
+	ErrRejectedByPolicy = errors.New("rejected by policy")
+)
+
+// multipleAddressesMessage is how USPS describes an ambiguous address in a 404 response
+const multipleAddressesMessage = "more than one address was found"
+
+// Is reports whether the error matches one of the sentinel errors, by HTTP status
+func (e *Error) Is(target error) bool {
+	switch e.HTTPStatus {
+	case http.StatusBadRequest:
+		return target == ErrInvalidInput
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		if e.isMultipleAddresses() {
+			return target == ErrMultipleAddresses
+		}
+		return target == ErrAddressNotFound
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return target == ErrServiceUnavailable
+	}
+	return false
+}
+
+// isMultipleAddresses reports whether a 404 was caused by an ambiguous address
+func (e *Error) isMultipleAddresses() bool {
+	messages := []string{e.Title, e.Detail}
+	for _, detail := range e.Errors {
+		messages = append(messages, detail.Title, detail.Detail)
+	}
+	for _, message := range messages {
+		if strings.Contains(strings.ToLower(message), multipleAddressesMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// inputError is an address rejected locally before a request; it matches ErrInvalidInput
+type inputError struct {
+	message string
+}
+
+func (e *inputError) Error() string {
+	return e.message
+}
+
+func (e *inputError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// invalidInputf returns an error that matches ErrInvalidInput
+func invalidInputf(format string, args ...any) error {
+	return &inputError{message: fmt.Sprintf(format, args...)}
+}
+
+// parseRetryAfter parses a Retry-After header, given either as seconds or an HTTP date
+// Returns 0 if the header is missing or invalid
+func parseRetryAfter(header string) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -113,7 +113,7 @@ func main() {
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 			if uspsErr, ok := err.(*uspsaddr.Error); ok {
-				fmt.Printf("USPS Error: status %q code %q title %q detail %q\n", uspsErr.Status, uspsErr.Code, uspsErr.Title, uspsErr.Detail)
+				fmt.Printf("USPS Error: status %d code %q title %q detail %q\n", uspsErr.HTTPStatus, uspsErr.Code, uspsErr.Title, uspsErr.Detail)
 				if uspsErr.Source != nil {
 					fmt.Printf("  Parameter: %s\n", uspsErr.Source.Parameter)
 					fmt.Printf("  Example: %s\n", uspsErr.Source.Example)
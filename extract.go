@@ -0,0 +1,239 @@
+package uspsaddr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// AddressCandidate is a postal address found in free text
+type AddressCandidate struct {
+	// The text of the address block as it appeared in the input
+	Text string
+
+	// Byte offsets of the address block in the input: input[Start:End] == Text
+	Start int
+	End   int
+
+	// The parsed address
+	Address Address
+
+	// How sure the parser is about each field
+	Confidence map[Field]Confidence
+
+	// The USPS validation result, set by Client.ExtractValidAddresses
+	Result *ValidationResult
+}
+
+// extractMaxLookback limits how far before the state and ZIP code an address block can start
+const extractMaxLookback = 160
+
+// extractMaxLines limits how many lines an address block can span
+const extractMaxLines = 4
+
+// lastLinePattern matches the "state ZIP" anchor at the end of an address block
+// State codes must be upper case so words like "in" and "me" don't match
+var lastLinePattern = regexp.MustCompile(lastLineExpr())
+
+// houseNumberPattern matches a number that could start a street line
+var houseNumberPattern = regexp.MustCompile(`\d+[A-Za-z]?\b`)
+
+// poBoxPattern matches the start of a PO Box line
+var poBoxPattern = regexp.MustCompile(`(?i)\b(?:P\.?\s*O\.?\s*B(?:OX)?|POST\s+OFFICE\s+BOX)\s*#?\s*\d`)
+
+// militaryLinePattern matches the start of a military unit line ("PSC 1234")
+var militaryLinePattern = regexp.MustCompile(`(?i)\b(?:PSC|UNIT|CMR)\s+\d`)
+
+// lastLineExpr builds the regular expression source for lastLinePattern
+func lastLineExpr() string {
+	codes := make([]string, 0, len(stateRegistry))
+	for _, info := range stateRegistry {
+		codes = append(codes, info.Code[:1]+`\.?`+info.Code[1:]+`\.?`)
+	}
+
+	// Longest first so "West Virginia" wins over "Virginia"
+	var names []string
+	for _, name := range stateNames() {
+		names = append(names, strings.ReplaceAll(name, " ", `\.?\s+`)+`\.?`)
+	}
+
+	return `\b(?:` + strings.Join(codes, "|") + `|(?i:` + strings.Join(names, "|") + `)),?\s+\d{5}(?:-\d{4})?\b`
+}
+
+// ExtractAddresses finds US postal addresses embedded in free text such as email bodies
+// Each address block must end with a state and ZIP code and start with a street line
+func ExtractAddresses(text string) []AddressCandidate {
+	var candidates []AddressCandidate
+
+	prevEnd := 0
+	for _, loc := range lastLinePattern.FindAllStringIndex(text, -1) {
+		if loc[0] < prevEnd {
+			continue
+		}
+
+		candidate, ok := extractBlock(text, prevEnd, loc[0], loc[1])
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, candidate)
+		prevEnd = candidate.End
+	}
+
+	return candidates
+}
+
+// ExtractValidAddresses finds addresses in free text and validates each one with USPS
+// Only addresses whose primary number is DPV confirmed (Y, D or S) are returned
+// Alternatives found with Config.CandidateBudget are never used, since they aren't the address
+// in the text
+func (c *Client) ExtractValidAddresses(ctx context.Context, text string) ([]AddressCandidate, error) {
+	var valid []AddressCandidate
+
+	for _, candidate := range ExtractAddresses(text) {
+		results, err := c.ValidateAddress(ctx, &candidate.Address)
+		if err != nil {
+			// The address being rejected just means it wasn't a real one
+			if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrAddressNotFound) || errors.Is(err, ErrMultipleAddresses) || errors.Is(err, ErrRejectedByPolicy) {
+				continue
+			}
+			return nil, err
+		}
+
+		for i := range results {
+			info := results[i].AdditionalInfo
+			if info == nil || results[i].Candidate != nil {
+				continue
+			}
+			if info.DPVConfirmation.Confirmed() {
+				candidate.Result = &results[i]
+				valid = append(valid, candidate)
+				break
+			}
+		}
+	}
+
+	return valid, nil
+}
+
+// extractBlock looks backwards from the state and ZIP anchor at text[anchorStart:anchorEnd]
+// for the street line that starts the address block
+// The block can't start before minStart
+func extractBlock(text string, minStart, anchorStart, anchorEnd int) (AddressCandidate, bool) {
+	windowStart := max(minStart, anchorStart-extractMaxLookback)
+
+	// Don't look back further than a few lines
+	lines := 0
+	for i := anchorStart - 1; i >= windowStart; i-- {
+		if text[i] == '\n' {
+			lines++
+			if lines >= extractMaxLines {
+				windowStart = i + 1
+				break
+			}
+		}
+	}
+
+	// Try the shortest block first so surrounding text isn't pulled into the street line
+	starts := streetLineStarts(text, windowStart, anchorStart)
+	for i := len(starts) - 1; i >= 0; i-- {
+		start := starts[i]
+		parsed, err := ParseAddressDetailed(text[start:anchorEnd])
+		if err != nil || parsed.Address.City == "" {
+			continue
+		}
+		// A street without a suffix ("100 Broadway") needs a comma or line break before the city
+		separated := parsed.Confidence[FieldCity] == ConfidenceHigh
+		if !looksLikeStreetLine(parsed.Address.StreetAddress, separated) {
+			continue
+		}
+
+		return AddressCandidate{
+			Text:       text[start:anchorEnd],
+			Start:      start,
+			End:        anchorEnd,
+			Address:    parsed.Address,
+			Confidence: parsed.Confidence,
+		}, true
+	}
+
+	return AddressCandidate{}, false
+}
+
+// streetLineStarts returns the offsets in text[from:to] where a street line could start, in order
+func streetLineStarts(text string, from, to int) []int {
+	window := text[from:to]
+
+	var starts []int
+	for _, loc := range houseNumberPattern.FindAllStringIndex(window, -1) {
+		// Must be the start of a word, not "#15" or the "4" in "I-25"
+		if loc[0] > 0 {
+			prev := rune(window[loc[0]-1])
+			if !unicode.IsSpace(prev) && !strings.ContainsRune(`,;:("'`, prev) {
+				continue
+			}
+		}
+		// Nor a unit or box number ("PSC 1234", "APT 15", "BOX 5678")
+		if words := strings.Fields(window[:loc[0]]); len(words) > 0 && isDesignatorWord(words[len(words)-1]) {
+			continue
+		}
+		starts = append(starts, from+loc[0])
+	}
+	for _, loc := range poBoxPattern.FindAllStringIndex(window, -1) {
+		starts = append(starts, from+loc[0])
+	}
+	for _, loc := range militaryLinePattern.FindAllStringIndex(window, -1) {
+		starts = append(starts, from+loc[0])
+	}
+
+	sort.Ints(starts)
+	return starts
+}
+
+// looksLikeStreetLine reports whether a parsed street line has the shape of a delivery address
+// Unless suffixOptional is set, a numbered street must have a street suffix ("123 Main St", but not "5 pm")
+// or a Spanish street type
+func looksLikeStreetLine(street string, suffixOptional bool) bool {
+	words := strings.Fields(street)
+	if len(words) < 2 {
+		return false
+	}
+
+	if strings.EqualFold(words[0], "PO") && strings.EqualFold(words[1], "BOX") {
+		return true
+	}
+
+	if militaryStreetPattern.MatchString(street) {
+		return true
+	}
+
+	if !startsWithDigit(words) {
+		return false
+	}
+
+	// Puerto Rico streets put the type first ("150 CALLE LUNA")
+	if suffixOptional || hasSpanishStreetType(words) {
+		return true
+	}
+
+	for _, word := range words[2:] {
+		if _, ok := streetSuffix(word); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isDesignatorWord reports whether a word introduces a unit or box number
+func isDesignatorWord(word string) bool {
+	word = strings.ToUpper(strings.Trim(word, ".#"))
+	switch word {
+	case "BOX", "POB", "PSC", "CMR":
+		return true
+	}
+	_, ok := lookupSecondaryUnit(word)
+	return ok
+}
@@ -0,0 +1,112 @@
+package uspsaddr
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestExtractAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []AddressCandidate
+	}{
+		{
+			name: "inline with a secondary unit",
+			text: "Please ship to 1820 Mary Ave Apt 15, Boulder, CO 80302 by Friday.",
+			want: []AddressCandidate{{
+				Text: "1820 Mary Ave Apt 15, Boulder, CO 80302", Start: 15, End: 54,
+				Address: Address{StreetAddress: "1820 Mary Ave", SecondaryAddress: "Apt 15", City: "Boulder", State: "CO", ZIPCode: "80302"},
+			}},
+		},
+		{
+			name: "block without a suffix",
+			text: "Hi,\nmy new address is\n100 Broadway\nDenver, CO 80203\nthanks",
+			want: []AddressCandidate{{
+				Text: "100 Broadway\nDenver, CO 80203", Start: 22, End: 51,
+				Address: Address{StreetAddress: "100 Broadway", City: "Denver", State: "CO", ZIPCode: "80203"},
+			}},
+		},
+		{
+			name: "state name and ZIP+4, after a time that isn't a street",
+			text: "Meet at 5 pm in Denver CO 80203 or at 200 E Colfax Ave, Denver, Colorado 80203-1234.",
+			want: []AddressCandidate{{
+				Text: "200 E Colfax Ave, Denver, Colorado 80203-1234", Start: 38, End: 83,
+				Address: Address{StreetAddress: "200 E Colfax Ave", City: "Denver", State: "CO", ZIPCode: "80203", ZIPPlus4: "1234"},
+			}},
+		},
+		{
+			name: "PO Box and abbreviated state name",
+			text: "Send it to PO Box 123, Raleigh, N. Carolina 27601",
+			want: []AddressCandidate{{
+				Text: "PO Box 123, Raleigh, N. Carolina 27601", Start: 11, End: 49,
+				Address: Address{StreetAddress: "PO BOX 123", City: "Raleigh", State: "NC", ZIPCode: "27601"},
+			}},
+		},
+		{
+			name: "military",
+			text: "PSC 1234 Box 5678, APO AE 09001",
+			want: []AddressCandidate{{
+				Text: "PSC 1234 Box 5678, APO AE 09001", Start: 0, End: 31,
+				Address: Address{StreetAddress: "PSC 1234 Box 5678", City: "APO", State: "AE", ZIPCode: "09001"},
+			}},
+		},
+		{
+			name: "two addresses",
+			text: "Old: 12 Elm St, Boulder, CO 80302. New: 34 Oak Rd, Boulder, CO 80304.",
+			want: []AddressCandidate{
+				{
+					Text: "12 Elm St, Boulder, CO 80302", Start: 5, End: 33,
+					Address: Address{StreetAddress: "12 Elm St", City: "Boulder", State: "CO", ZIPCode: "80302"},
+				},
+				{
+					Text: "34 Oak Rd, Boulder, CO 80304", Start: 40, End: 68,
+					Address: Address{StreetAddress: "34 Oak Rd", City: "Boulder", State: "CO", ZIPCode: "80304"},
+				},
+			},
+		},
+		{
+			name: "state and ZIP without a street",
+			text: "No address here, just CO 80302.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractAddresses(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExtractAddresses() found %d addresses, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				c := got[i]
+				if c.Text != want.Text || c.Start != want.Start || c.End != want.End || c.Address != want.Address {
+					t.Errorf("address %d = %q [%d:%d] %+v\nwant %q [%d:%d] %+v", i, c.Text, c.Start, c.End, c.Address, want.Text, want.Start, want.End, want.Address)
+				}
+				if tt.text[c.Start:c.End] != c.Text {
+					t.Errorf("address %d: text[%d:%d] = %q, want %q", i, c.Start, c.End, tt.text[c.Start:c.End], c.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractValidAddressesIgnoresAlternatives(t *testing.T) {
+	// The address in the text matches more than one address and isn't confirmed, but adding
+	// a directional finds one that is
+	client := newTestClient(t, Config{CandidateBudget: 4}, func(query url.Values) (int, string) {
+		if query.Get("streetAddress") == "100 N Main St" {
+			return http.StatusOK, confirmedResponse("100 N MAIN ST")
+		}
+		return http.StatusOK, `{"address": {"streetAddress": "100 MAIN ST", "city": "BOULDER", "state": "CO", "ZIPCode": "80302"}, "additionalInfo": {"DPVConfirmation": "N"}, "corrections": [{"code": "22", "text": "Multiple addresses were found"}]}`
+	})
+
+	valid, err := client.ExtractValidAddresses(context.Background(), "Ship to 100 Main St, Boulder, CO 80302 please")
+	if err != nil {
+		t.Fatalf("ExtractValidAddresses() error = %v", err)
+	}
+	if len(valid) != 0 {
+		t.Errorf("ExtractValidAddresses() = %+v, want no addresses", valid)
+	}
+}
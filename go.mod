@@ -5,6 +5,7 @@ go 1.23
 require (
 	github.com/oapi-codegen/runtime v1.1.1
 	github.com/tadhunt/logger v0.0.0-20250303180812-6aad7c71b986
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
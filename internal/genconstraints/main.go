@@ -0,0 +1,167 @@
+// genconstraints generates the request parameter constraints in constraints.gen.go
+// from the USPS Addresses OpenAPI spec, so local input validation stays in sync with it
+//
+// Usage: go run ./internal/genconstraints -spec usps-addresses-v3r2_2.yaml -out constraints.gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fieldNames maps USPS query parameter names to the uspsaddr Field constants
+var fieldNames = map[string]string{
+	"firm":             "FieldFirm",
+	"streetAddress":    "FieldStreetAddress",
+	"secondaryAddress": "FieldSecondaryAddress",
+	"city":             "FieldCity",
+	"state":            "FieldState",
+	"urbanization":     "FieldUrbanization",
+	"ZIPCode":          "FieldZIPCode",
+	"ZIPPlus4":         "FieldZIPPlus4",
+}
+
+// operations lists the spec operations to generate constraints for, and the variable to put them in
+var operations = []struct {
+	OperationID string
+	Variable    string
+}{
+	{"get-address", "addressConstraints"},
+	{"get-ZIPCode", "zipCodeConstraints"},
+}
+
+type spec struct {
+	Paths      map[string]map[string]operation `yaml:"paths"`
+	Components struct {
+		Parameters map[string]parameter `yaml:"parameters"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	OperationID string      `yaml:"operationId"`
+	Parameters  []parameter `yaml:"parameters"`
+}
+
+type parameter struct {
+	Ref      string `yaml:"$ref"`
+	Name     string `yaml:"name"`
+	In       string `yaml:"in"`
+	Required bool   `yaml:"required"`
+	Schema   struct {
+		MaxLength *int   `yaml:"maxLength"`
+		MinLength *int   `yaml:"minLength"`
+		Pattern   string `yaml:"pattern"`
+	} `yaml:"schema"`
+}
+
+func main() {
+	specPath := flag.String("spec", "usps-addresses-v3r2_2.yaml", "OpenAPI spec to read")
+	outPath := flag.String("out", "constraints.gen.go", "Go file to write")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var s spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		log.Fatalf("%s: %v", *specPath, err)
+	}
+
+	src, err := generate(&s, filepath.Base(*specPath))
+	if err != nil {
+		log.Fatalf("%s: %v", *specPath, err)
+	}
+
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the formatted source of constraints.gen.go
+func generate(s *spec, specName string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by internal/genconstraints from %s; DO NOT EDIT.\n\n", specName)
+	fmt.Fprintf(&b, "package uspsaddr\n\n")
+	fmt.Fprintf(&b, "import \"regexp\"\n")
+
+	for _, op := range operations {
+		params, err := operationParameters(s, op.OperationID)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&b, "\n// %s are the query parameter constraints of the %s operation\n", op.Variable, op.OperationID)
+		fmt.Fprintf(&b, "var %s = []fieldConstraint{\n", op.Variable)
+		for _, p := range params {
+			field, ok := fieldNames[p.Name]
+			if !ok {
+				return nil, fmt.Errorf("%s: no Field for parameter %q", op.OperationID, p.Name)
+			}
+			fmt.Fprintf(&b, "{\nField: %s,\nParameter: %q,\n", field, p.Name)
+			if p.Required {
+				fmt.Fprintf(&b, "Required: true,\n")
+			}
+			if p.Schema.MinLength != nil && *p.Schema.MinLength > 0 {
+				fmt.Fprintf(&b, "MinLength: %d,\n", *p.Schema.MinLength)
+			}
+			if p.Schema.MaxLength != nil {
+				fmt.Fprintf(&b, "MaxLength: %d,\n", *p.Schema.MaxLength)
+			}
+			if p.Schema.Pattern != "" {
+				fmt.Fprintf(&b, "Pattern: regexp.MustCompile(%s),\n", quote(p.Schema.Pattern))
+			}
+			fmt.Fprintf(&b, "},\n")
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// operationParameters returns the query parameters of an operation, with references resolved
+func operationParameters(s *spec, operationID string) ([]parameter, error) {
+	for _, methods := range s.Paths {
+		for _, op := range methods {
+			if op.OperationID != operationID {
+				continue
+			}
+
+			var params []parameter
+			for _, p := range op.Parameters {
+				if ref := p.Ref; ref != "" {
+					name, ok := strings.CutPrefix(ref, "#/components/parameters/")
+					if !ok {
+						return nil, fmt.Errorf("%s: unsupported reference %q", operationID, ref)
+					}
+					if p, ok = s.Components.Parameters[name]; !ok {
+						return nil, fmt.Errorf("%s: unknown parameter %q", operationID, ref)
+					}
+				}
+				if p.In == "query" {
+					params = append(params, p)
+				}
+			}
+			return params, nil
+		}
+	}
+	return nil, fmt.Errorf("operation %q not found", operationID)
+}
+
+// quote returns a Go string literal for a regular expression, preferring a raw string
+func quote(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,114 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MilitaryAddressError is returned when the city, state and ZIP code of a military
+// (APO/FPO/DPO) address don't agree with each other
+type MilitaryAddressError struct {
+	// The field that is inconsistent with the rest of the address
+	Field Field
+
+	Message string
+}
+
+func (e *MilitaryAddressError) Error() string {
+	return e.Message
+}
+
+func (e *MilitaryAddressError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// militaryCities are the "city" names used for military mail
+var militaryCities = map[string]bool{
+	"APO": true, // Army/Air Post Office
+	"FPO": true, // Fleet Post Office
+	"DPO": true, // Diplomatic Post Office
+}
+
+// militaryStreetPattern matches military unit and box lines:
+// "PSC 1234 BOX 5678", "UNIT 1234 BOX 5678" and "CMR 450 BOX 123"
+var militaryStreetPattern = regexp.MustCompile(`(?i)^(PSC|UNIT|CMR)\s*#?\s*(\d+)(?:\s+BOX\s*#?\s*(\d+))?$`)
+
+// militaryBoxPattern matches a "BOX 5678" line given as the secondary address
+var militaryBoxPattern = regexp.MustCompile(`(?i)^BOX\s*#?\s*(\d+)$`)
+
+// IsMilitary reports whether the address is an APO/FPO/DPO military address
+// A PSC/UNIT/CMR street line alone isn't enough, since civilian addresses can look the same
+// ("Unit 5"); it only counts with a military ZIP code
+func (a Address) IsMilitary() bool {
+	if militaryCities[strings.ToUpper(a.City)] {
+		return true
+	}
+	if info, ok := stateByCode[strings.ToUpper(a.State)]; ok && info.Category == StateCategoryMilitary {
+		return true
+	}
+	return militaryStreetPattern.MatchString(a.StreetAddress) && militaryStateForZIP(a.ZIPCode) != ""
+}
+
+// prepareMilitary puts a military address into the form USPS expects
+// The unit and box are combined into one upper-case street line ("PSC 1234 BOX 5678"),
+// the city is upper-cased, and a missing state is filled in from the ZIP code
+func prepareMilitary(address *Address) {
+	address.City = strings.ToUpper(address.City)
+
+	street := address.StreetAddress
+	if m := militaryBoxPattern.FindStringSubmatch(address.SecondaryAddress); m != nil {
+		street += " BOX " + m[1]
+	}
+	if m := militaryStreetPattern.FindStringSubmatch(street); m != nil {
+		address.StreetAddress = strings.ToUpper(m[1]) + " " + m[2]
+		if m[3] != "" {
+			address.StreetAddress += " BOX " + m[3]
+			address.SecondaryAddress = ""
+		}
+	}
+
+	if address.State == "" {
+		address.State = militaryStateForZIP(address.ZIPCode)
+	}
+}
+
+// checkMilitary verifies that the city, state and ZIP code of a military address agree
+func checkMilitary(address Address) error {
+	militaryCity := militaryCities[address.City]
+	info, knownState := stateByCode[address.State]
+	militaryState := knownState && info.Category == StateCategoryMilitary
+
+	if militaryCity && !militaryState {
+		return &MilitaryAddressError{
+			Field:   FieldState,
+			Message: fmt.Sprintf("%s addresses must use state AA, AE or AP, not %q", address.City, address.State),
+		}
+	}
+
+	if militaryState && address.City != "" && !militaryCity {
+		return &MilitaryAddressError{
+			Field:   FieldCity,
+			Message: fmt.Sprintf("state %s addresses must use city APO, FPO or DPO, not %q", address.State, address.City),
+		}
+	}
+
+	if militaryState && address.ZIPCode != "" {
+		if expected := militaryStateForZIP(address.ZIPCode); expected != address.State {
+			return &MilitaryAddressError{
+				Field:   FieldZIPCode,
+				Message: fmt.Sprintf("ZIP code %s is not in the %s range", address.ZIPCode, info.Name),
+			}
+		}
+	}
+
+	return nil
+}
+
+// militaryStateForZIP returns the Armed Forces region a ZIP code belongs to, or "" if none
+func militaryStateForZIP(zip string) string {
+	if info, ok := StateForZIP(zip); ok && info.Category == StateCategoryMilitary {
+		return info.Code
+	}
+	return ""
+}
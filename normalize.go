@@ -0,0 +1,140 @@
+package uspsaddr
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// zipPlus4Pattern matches a 9-digit ZIP code with or without a separator
+var zipPlus4Pattern = regexp.MustCompile(`^(\d{5})[- ]?(\d{4})$`)
+
+// NormalizeAddress cleans up address input before it is sent to USPS
+// It trims and collapses whitespace, strips stray punctuation, writes PO Boxes as "PO BOX",
+// splits 9-digit ZIP codes into ZIPCode and ZIPPlus4, and converts full state names to codes
+// Accented letters and fractions ("123½") are left for Transliterate, which ValidateAddress runs first
+func NormalizeAddress(address Address) Address {
+	result := Address{
+		Firm:                      collapseSpace(address.Firm),
+		StreetAddress:             normalizePOBox(normalizeLine(address.StreetAddress)),
+		StreetAddressAbbreviation: normalizePOBox(normalizeLine(address.StreetAddressAbbreviation)),
+		SecondaryAddress:          normalizeLine(address.SecondaryAddress),
+		City:                      normalizeCity(address.City),
+		CityAbbreviation:          normalizeCity(address.CityAbbreviation),
+		State:                     normalizeState(address.State),
+		ZIPCode:                   strings.Join(strings.Fields(address.ZIPCode), ""),
+		ZIPPlus4:                  strings.Join(strings.Fields(address.ZIPPlus4), ""),
+		Urbanization:              normalizeCity(address.Urbanization),
+	}
+
+	// Split ZIP+4 out of the ZIP code field
+	if m := zipPlus4Pattern.FindStringSubmatch(collapseSpace(address.ZIPCode)); m != nil {
+		result.ZIPCode = m[1]
+		if result.ZIPPlus4 == "" {
+			result.ZIPPlus4 = m[2]
+		}
+	}
+
+	return result
+}
+
+// preparedAddress is an address that has been cleaned up for a USPS request,
+// along with a record of the changes that were made
+type preparedAddress struct {
+	Address Address
+
+	Transliterations []Transliteration
+
+	SecondarySplit *SecondarySplit
+
+	// Whether this is an APO/FPO/DPO military address
+	Military bool
+
+	// Whether this is a Puerto Rico address
+	PuertoRico bool
+}
+
+// prepareAddress cleans up address input before it is sent to USPS
+func prepareAddress(address Address) preparedAddress {
+	// Transliterate first so accented letters and full-width digits are normalized like any other
+	address, transliterations := Transliterate(address)
+	prepared := preparedAddress{
+		Address:          NormalizeAddress(address),
+		Transliterations: transliterations,
+	}
+
+	// "UNIT 1234 BOX 5678" is a military street line, not a secondary unit
+	if prepared.Address.IsMilitary() {
+		prepareMilitary(&prepared.Address)
+		prepared.Military = true
+		return prepared
+	}
+
+	// The urbanization comes out first so it isn't mistaken for part of the street
+	if prepared.Address.IsPuertoRico() {
+		preparePuertoRico(&prepared.Address)
+		prepared.PuertoRico = true
+	}
+	prepared.SecondarySplit = splitSecondary(&prepared.Address)
+
+	return prepared
+}
+
+// collapseSpace trims the string and collapses runs of whitespace to a single space
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// normalizeLine strips punctuation USPS doesn't use from a street or secondary line
+// Hyphens (123-45), slashes (1/2) and the # unit designator are kept
+func normalizeLine(s string) string {
+	return collapseSpace(stripPunctuation(s, "-/#&"))
+}
+
+// normalizeCity strips punctuation from a city or urbanization name, keeping hyphens
+func normalizeCity(s string) string {
+	return collapseSpace(stripPunctuation(s, "-"))
+}
+
+// normalizeState converts a state name, code or common spelling to its 2-letter code
+// Unrecognized values, including misspellings, are returned cleaned up but otherwise unchanged,
+// so validation reports them with a suggestion rather than guessing
+func normalizeState(s string) string {
+	if info, ok := LookupState(s); ok {
+		return info.Code
+	}
+	return strings.ToUpper(collapseSpace(stripPunctuation(s, "")))
+}
+
+// stripPunctuation removes punctuation other than the characters in keep
+// Periods after a single letter are dropped so initialisms like "P.O." and "N.W."
+// collapse to "PO" and "NW", and apostrophes are dropped so "Mary's" and "O'Brien" become
+// "Marys" and "OBrien" as USPS writes them; any other punctuation becomes a space
+// Letters and numbers, including non-ASCII ones such as "Ñ" and "½", are kept for
+// Transliterate to handle
+func stripPunctuation(s, keep string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	runLength := 0 // letters/digits since the last separator
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r):
+			b.WriteRune(r)
+			runLength++
+			continue
+		case r == '\'' || r == '‘' || r == '’':
+			// Dropped without ending the word
+			continue
+		case unicode.IsSpace(r), strings.ContainsRune(keep, r):
+			b.WriteRune(r)
+		case r == '.' && runLength == 1:
+			// Dropped; starting a new run lets "A.B.C." collapse fully
+		default:
+			b.WriteRune(' ')
+		}
+		runLength = 0
+	}
+
+	return b.String()
+}
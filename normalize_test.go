@@ -0,0 +1,65 @@
+package uspsaddr
+
+import "testing"
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Address
+		want  Address
+	}{
+		{
+			name:  "whitespace and state name",
+			input: Address{StreetAddress: "  123   Main St ", City: " Boulder ", State: "colorado"},
+			want:  Address{StreetAddress: "123 Main St", City: "Boulder", State: "CO"},
+		},
+		{
+			name:  "possessive",
+			input: Address{StreetAddress: "12 St. Mary's Rd", City: "Boulder", State: "CO"},
+			want:  Address{StreetAddress: "12 St Marys Rd", City: "Boulder", State: "CO"},
+		},
+		{
+			name:  "Irish name with a curly apostrophe",
+			input: Address{StreetAddress: "5 O’Brien St", City: "Boulder", State: "CO"},
+			want:  Address{StreetAddress: "5 OBrien St", City: "Boulder", State: "CO"},
+		},
+		{
+			name:  "Italian name",
+			input: Address{StreetAddress: "40 D'Angelo Dr", City: "Boise", State: "ID"},
+			want:  Address{StreetAddress: "40 DAngelo Dr", City: "Boise", State: "ID"},
+		},
+		{
+			name:  "initialisms",
+			input: Address{StreetAddress: "P.O. Box 12", City: "Washington", State: "D.C."},
+			want:  Address{StreetAddress: "PO BOX 12", City: "Washington", State: "DC"},
+		},
+		{
+			name:  "kept punctuation",
+			input: Address{StreetAddress: "123-45 Main St, #4", SecondaryAddress: "Apt. 2B", City: "Winston-Salem", State: "NC"},
+			want:  Address{StreetAddress: "123-45 Main St #4", SecondaryAddress: "Apt 2B", City: "Winston-Salem", State: "NC"},
+		},
+		{
+			name:  "fractions and accents are left for transliteration",
+			input: Address{StreetAddress: "123½ Main St", City: "Peñasco", State: "NM"},
+			want:  Address{StreetAddress: "123½ Main St", City: "Peñasco", State: "NM"},
+		},
+		{
+			name:  "ZIP+4 with a hyphen",
+			input: Address{StreetAddress: "1 Main St", State: "CO", ZIPCode: "80302-1234"},
+			want:  Address{StreetAddress: "1 Main St", State: "CO", ZIPCode: "80302", ZIPPlus4: "1234"},
+		},
+		{
+			name:  "ZIP+4 without a separator",
+			input: Address{StreetAddress: "1 Main St", State: "CO", ZIPCode: " 803021234 "},
+			want:  Address{StreetAddress: "1 Main St", State: "CO", ZIPCode: "80302", ZIPPlus4: "1234"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeAddress(tt.input); got != tt.want {
+				t.Errorf("NormalizeAddress() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
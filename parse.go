@@ -0,0 +1,372 @@
+package uspsaddr
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Confidence indicates how sure the parser is about a field
+type Confidence int
+
+const (
+	// ConfidenceNone means the field was not found
+	ConfidenceNone Confidence = iota
+
+	// ConfidenceLow means the field was guessed from its position alone
+	ConfidenceLow
+
+	// ConfidenceMedium means the field was located using keywords such as a street suffix
+	ConfidenceMedium
+
+	// ConfidenceHigh means the field was unambiguous, such as a ZIP code or a comma separated city
+	ConfidenceHigh
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceNone:
+		return "none"
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	}
+	return fmt.Sprintf("Confidence(%d)", int(c))
+}
+
+// ParsedAddress contains an address parsed from a single string
+type ParsedAddress struct {
+	// The parsed address
+	Address Address
+
+	// How sure the parser is about each field it filled in
+	// Fields that weren't found are absent (ConfidenceNone)
+	Confidence map[Field]Confidence
+}
+
+// zipTokenPattern matches a 5 or 9-digit ZIP code
+var zipTokenPattern = regexp.MustCompile(`^\d{5}(-?\d{4})?$`)
+
+// ParseAddress splits a single-line address such as
+// "1820 Mary Ave Apt 15, Boulder CO 80302-1234" into its fields
+// The street line must start with a house number, or be a PO Box, rural route, highway contract,
+// general delivery or military line; anything else is an error
+func ParseAddress(s string) (*Address, error) {
+	parsed, err := ParseAddressDetailed(s)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed.Address, nil
+}
+
+// ParseAddressDetailed is like ParseAddress but also reports how confident it is in each field
+func ParseAddressDetailed(s string) (*ParsedAddress, error) {
+	segments := splitSegments(s)
+	if len(segments) == 0 {
+		return nil, invalidInputf("address string is empty")
+	}
+
+	parsed := &ParsedAddress{
+		Confidence: map[Field]Confidence{},
+	}
+	addr := &parsed.Address
+
+	// ZIP code is the last word
+	last := segments[len(segments)-1]
+	if zip := last[len(last)-1]; zipTokenPattern.MatchString(zip) {
+		addr.ZIPCode = zip
+		parsed.Confidence[FieldZIPCode] = ConfidenceHigh
+		segments = dropTrailingTokens(segments, 1)
+	}
+
+	// State comes before the ZIP code. Without a ZIP code or a comma to separate it from the
+	// street, a trailing "Ct" is more likely to be a street suffix than Connecticut.
+	if len(segments) > 0 {
+		last = segments[len(segments)-1]
+		ambiguous := addr.ZIPCode == "" && len(segments) == 1
+
+		// Leave at least one word for the street when everything is in a single segment
+		maxWords := len(last)
+		if len(segments) == 1 {
+			maxWords--
+		}
+
+		for n := min(4, maxWords); n >= 1; n-- {
+			words := strings.Join(last[len(last)-n:], " ")
+			if _, isSuffix := streetSuffix(words); ambiguous && isSuffix {
+				continue
+			}
+			if code, ok := stateCode(words); ok {
+				addr.State = code
+				parsed.Confidence[FieldState] = ConfidenceHigh
+				segments = dropTrailingTokens(segments, n)
+				break
+			}
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, invalidInputf("no street address found in %q", s)
+	}
+
+	foundLastLine := addr.State != "" || addr.ZIPCode != ""
+
+	var street, city, secondary []string
+	cityConfidence := ConfidenceNone
+	secondaryConfidence := ConfidenceNone
+
+	if len(segments) == 1 {
+		// No commas: the street and city run together
+		street = segments[0]
+		if foundLastLine {
+			street, city, cityConfidence = splitStreetCity(street)
+		}
+	} else {
+		city = segments[len(segments)-1]
+		cityConfidence = ConfidenceMedium
+		if foundLastLine {
+			cityConfidence = ConfidenceHigh
+		}
+
+		// The street line is the first segment that starts with a house number;
+		// anything before it is most likely a firm name
+		rest := segments[:len(segments)-1]
+
+		// A Puerto Rico urbanization gets a segment of its own ("Urb Las Gladiolas")
+		var urbanization [][]string
+		rest, urbanization = splitUrbanizationSegments(rest)
+		if len(urbanization) > 0 && len(rest) > 0 {
+			addr.Urbanization = standardizeUrbanization(urbanization[0])
+			parsed.Confidence[FieldUrbanization] = ConfidenceHigh
+		} else {
+			rest = segments[:len(segments)-1]
+		}
+
+		streetIndex := 0
+		for i, seg := range rest {
+			if startsWithDigit(seg) {
+				streetIndex = i
+				break
+			}
+		}
+		if streetIndex > 0 {
+			addr.Firm = joinSegments(rest[:streetIndex])
+			parsed.Confidence[FieldFirm] = ConfidenceLow
+		}
+		street = rest[streetIndex]
+
+		// Segments between the street and city are secondary units ("Apt 15")
+		if extra := rest[streetIndex+1:]; len(extra) > 0 {
+			secondaryConfidence = ConfidenceLow
+			if len(extra) == 1 && secondaryLength(extra[0], 0) == len(extra[0]) {
+				secondaryConfidence = ConfidenceHigh
+			}
+			for _, seg := range extra {
+				secondary = append(secondary, seg...)
+			}
+		}
+	}
+
+	if len(secondary) == 0 {
+		street, secondary = splitSecondaryTokens(street)
+		if len(secondary) > 0 {
+			secondaryConfidence = ConfidenceHigh
+		}
+	}
+
+	if len(street) == 0 {
+		return nil, invalidInputf("no street address found in %q", s)
+	}
+
+	addr.StreetAddress = strings.Join(street, " ")
+	parsed.Confidence[FieldStreetAddress] = ConfidenceMedium
+	if startsWithDigit(street) {
+		parsed.Confidence[FieldStreetAddress] = ConfidenceHigh
+	}
+
+	if len(secondary) > 0 {
+		addr.SecondaryAddress = strings.Join(secondary, " ")
+		parsed.Confidence[FieldSecondaryAddress] = secondaryConfidence
+	}
+
+	if len(city) > 0 {
+		addr.City = strings.Join(city, " ")
+		parsed.Confidence[FieldCity] = cityConfidence
+	}
+
+	parsed.Address = NormalizeAddress(*addr)
+
+	// What's left of a line such as "CO 80302" or "Boulder, CO 80302" isn't a street line
+	if parsed.Address.Type() == AddressTypeUnknown {
+		return nil, invalidInputf("no street address found in %q", s)
+	}
+
+	if parsed.Address.ZIPPlus4 != "" {
+		parsed.Confidence[FieldZIPPlus4] = ConfidenceHigh
+	}
+
+	return parsed, nil
+}
+
+// ValidateAddressString parses a single-line address and validates it
+func (c *Client) ValidateAddressString(ctx context.Context, s string) ([]ValidationResult, error) {
+	address, err := ParseAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	return c.ValidateAddress(ctx, address)
+}
+
+// splitSegments splits an address on commas, semicolons and line breaks into lists of words
+func splitSegments(s string) [][]string {
+	var segments [][]string
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '\r'
+	})
+	for _, part := range parts {
+		if words := strings.Fields(normalizePOBox(normalizeLine(part))); len(words) > 0 {
+			segments = append(segments, words)
+		}
+	}
+	return segments
+}
+
+// dropTrailingTokens removes n words from the end of the last segment, dropping it if it becomes empty
+func dropTrailingTokens(segments [][]string, n int) [][]string {
+	last := segments[len(segments)-1]
+	last = last[:len(last)-n]
+	if len(last) == 0 {
+		return segments[:len(segments)-1]
+	}
+	segments[len(segments)-1] = last
+	return segments
+}
+
+// joinSegments joins segments back into a comma separated string
+func joinSegments(segments [][]string) string {
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		parts[i] = strings.Join(seg, " ")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// splitUrbanizationSegments separates segments that start with URB from the rest
+func splitUrbanizationSegments(segments [][]string) (rest, urbanization [][]string) {
+	for _, seg := range segments {
+		if isUrbanizationWord(seg) && len(seg) > 1 {
+			urbanization = append(urbanization, seg)
+		} else {
+			rest = append(rest, seg)
+		}
+	}
+	return rest, urbanization
+}
+
+// startsWithDigit reports whether the first word starts with a digit
+func startsWithDigit(words []string) bool {
+	return len(words) > 0 && words[0] != "" && unicode.IsDigit(rune(words[0][0]))
+}
+
+// splitStreetCity splits a run of words into the street line and city
+// The street ends at the first street suffix after the street name, plus any
+// abbreviated post-directional and secondary unit that follow it
+func splitStreetCity(words []string) (street, city []string, confidence Confidence) {
+	// Military mail uses APO/FPO/DPO in place of the city ("PSC 1234 BOX 5678 APO")
+	if last := len(words) - 1; last > 0 && militaryCities[strings.ToUpper(words[last])] {
+		return words[:last], words[last:], ConfidenceHigh
+	}
+
+	// Start at 2 so the house number and street name come first ("100 Park Ave")
+	for i := 2; i < len(words); i++ {
+		if _, ok := streetSuffix(words[i]); !ok {
+			continue
+		}
+		end := i + 1
+		if end < len(words)-1 && len(words[end]) <= 2 {
+			if _, ok := directional(words[end]); ok {
+				end++
+			}
+		}
+		end += secondaryLength(words, end)
+		if end >= len(words) {
+			return words, nil, ConfidenceNone
+		}
+		return words[:end], words[end:], ConfidenceMedium
+	}
+
+	// No suffix ("1820 Mary Apt 15 Boulder"): a secondary unit still ends the street
+	for i := 1; i < len(words); i++ {
+		if n := secondaryLength(words, i); n > 0 && i+n < len(words) {
+			return words[:i+n], words[i+n:], ConfidenceMedium
+		}
+	}
+
+	// Otherwise guess that the city is the last word
+	if len(words) >= 3 {
+		return words[:len(words)-1], words[len(words)-1:], ConfidenceLow
+	}
+	return words, nil, ConfidenceNone
+}
+
+// splitSecondaryTokens splits a trailing secondary unit ("Apt 15", "#15", "Rear") off the street line
+func splitSecondaryTokens(words []string) (street, secondary []string) {
+	// Start at 1 so the designator can't be the house number
+	for i := 1; i < len(words); i++ {
+		n := secondaryLength(words, i)
+		if n == 0 {
+			continue
+		}
+		// Designators without a unit number ("Rear") only count at the end of the line
+		if unit, ok := lookupSecondaryUnit(words[i]); ok && !unit.RequiresRange && i+n != len(words) {
+			continue
+		}
+		return words[:i], words[i:]
+	}
+	return words, nil
+}
+
+// secondaryLength returns the number of words that make up a secondary unit starting at words[i]
+// Returns 0 if there is no secondary unit there
+func secondaryLength(words []string, i int) int {
+	if i >= len(words) {
+		return 0
+	}
+
+	word := words[i]
+	if strings.HasPrefix(word, "#") {
+		if word != "#" {
+			return 1
+		}
+		if i+1 < len(words) && isUnitNumber(words[i+1]) {
+			return 2
+		}
+		return 0
+	}
+
+	unit, ok := lookupSecondaryUnit(word)
+	if !ok {
+		return 0
+	}
+	if !unit.RequiresRange {
+		return 1
+	}
+	if i+1 < len(words) && isUnitNumber(words[i+1]) {
+		return 2
+	}
+	return 0
+}
+
+// isUnitNumber reports whether a word looks like a unit number ("15", "#15", "3A", "B")
+func isUnitNumber(word string) bool {
+	word = strings.TrimPrefix(word, "#")
+	if len(word) == 1 && unicode.IsLetter(rune(word[0])) {
+		return true
+	}
+	return strings.ContainsFunc(word, unicode.IsDigit)
+}
@@ -0,0 +1,137 @@
+package uspsaddr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Address
+		wantErr bool
+	}{
+		{
+			input: "1820 Mary Ave Apt 15, Boulder CO 80302-1234",
+			want:  Address{StreetAddress: "1820 Mary Ave", SecondaryAddress: "Apt 15", City: "Boulder", State: "CO", ZIPCode: "80302", ZIPPlus4: "1234"},
+		},
+		{
+			input: "1820 Mary Ave Apt 15 Boulder CO 80302",
+			want:  Address{StreetAddress: "1820 Mary Ave", SecondaryAddress: "Apt 15", City: "Boulder", State: "CO", ZIPCode: "80302"},
+		},
+		{
+			input: "Acme Inc, 500 Elm St, Suite 200, Boulder, CO 80302",
+			want:  Address{Firm: "Acme Inc", StreetAddress: "500 Elm St", SecondaryAddress: "Suite 200", City: "Boulder", State: "CO", ZIPCode: "80302"},
+		},
+		{
+			input: "P.O. Box 123 Raleigh NC 27601",
+			want:  Address{StreetAddress: "PO BOX 123", City: "Raleigh", State: "NC", ZIPCode: "27601"},
+		},
+		{
+			input: "PSC 1234 Box 5678 APO AE 09001",
+			want:  Address{StreetAddress: "PSC 1234 Box 5678", City: "APO", State: "AE", ZIPCode: "09001"},
+		},
+		{
+			input: "RR 2 Box 15, Hope, AR 71801",
+			want:  Address{StreetAddress: "RR 2 Box 15", City: "Hope", State: "AR", ZIPCode: "71801"},
+		},
+		{
+			input: "General Delivery, Boulder, CO 80302",
+			want:  Address{StreetAddress: "General Delivery", City: "Boulder", State: "CO", ZIPCode: "80302"},
+		},
+		{
+			input: "100 Broadway, New York, NY",
+			want:  Address{StreetAddress: "100 Broadway", City: "New York", State: "NY"},
+		},
+		{
+			input: "100 Main St Hartford Ct 06103",
+			want:  Address{StreetAddress: "100 Main St", City: "Hartford", State: "CT", ZIPCode: "06103"},
+		},
+		{
+			// Without a ZIP code or comma, "Ct" is the street suffix rather than Connecticut
+			input: "12 Elm Ct",
+			want:  Address{StreetAddress: "12 Elm Ct"},
+		},
+		{
+			input: "123 1/2 Main St, Boulder, N. Carolina 27601",
+			want:  Address{StreetAddress: "123 1/2 Main St", City: "Boulder", State: "NC", ZIPCode: "27601"},
+		},
+		{input: "CO 80302", wantErr: true},
+		{input: "Boulder, CO 12345", wantErr: true},
+		{input: "Boulder CO 80302", wantErr: true},
+		{input: "Acme Inc, Boulder, CO 80302", wantErr: true},
+		{input: "   ,  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAddress(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("ParseAddress() = %+v, %v, want an ErrInvalidInput error", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAddress() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseAddress() = %+v\nwant %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAddressDetailedConfidence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[Field]Confidence
+	}{
+		{
+			input: "1820 Mary Ave Apt 15, Boulder CO 80302",
+			want: map[Field]Confidence{
+				FieldStreetAddress:    ConfidenceHigh,
+				FieldSecondaryAddress: ConfidenceHigh,
+				FieldCity:             ConfidenceHigh,
+				FieldState:            ConfidenceHigh,
+				FieldZIPCode:          ConfidenceHigh,
+			},
+		},
+		{
+			input: "Acme Inc, 500 Elm St, Boulder CO 80302",
+			want: map[Field]Confidence{
+				FieldFirm:          ConfidenceLow,
+				FieldStreetAddress: ConfidenceHigh,
+				FieldCity:          ConfidenceHigh,
+				FieldState:         ConfidenceHigh,
+				FieldZIPCode:       ConfidenceHigh,
+			},
+		},
+		{
+			input: "PO Box 123 Raleigh NC 27601",
+			want: map[Field]Confidence{
+				FieldStreetAddress: ConfidenceMedium,
+				FieldCity:          ConfidenceLow,
+				FieldState:         ConfidenceHigh,
+				FieldZIPCode:       ConfidenceHigh,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parsed, err := ParseAddressDetailed(tt.input)
+			if err != nil {
+				t.Fatalf("ParseAddressDetailed() error = %v", err)
+			}
+			if len(parsed.Confidence) != len(tt.want) {
+				t.Errorf("Confidence = %v, want %v", parsed.Confidence, tt.want)
+			}
+			for field, want := range tt.want {
+				if got := parsed.Confidence[field]; got != want {
+					t.Errorf("Confidence[%s] = %v, want %v", field, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package uspsaddr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decision is what a Policy decides about a validated address
+type Decision int
+
+const (
+	// DecisionAccept means no rule fired
+	DecisionAccept Decision = iota
+
+	// DecisionWarn means the address can be used, but a rule flagged it
+	DecisionWarn
+
+	// DecisionReject means the address should not be used
+	DecisionReject
+)
+
+func (d Decision) String() string {
+	switch d {
+	case DecisionAccept:
+		return "accept"
+	case DecisionWarn:
+		return "warn"
+	case DecisionReject:
+		return "reject"
+	}
+	return fmt.Sprintf("Decision(%d)", int(d))
+}
+
+// MarshalText writes the decision as "accept", "warn" or "reject"
+func (d Decision) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads "accept", "warn" or "reject", so decisions can be written by name in
+// YAML and JSON policies
+func (d *Decision) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "accept":
+		*d = DecisionAccept
+	case "warn":
+		*d = DecisionWarn
+	case "reject":
+		*d = DecisionReject
+	default:
+		return fmt.Errorf("unknown decision %q, expected accept, warn or reject", text)
+	}
+	return nil
+}
+
+// Policy is a set of rules for accepting validated addresses, such as "reject vacant addresses"
+// Policies are usually loaded from YAML or JSON with LoadPolicy or ParsePolicy, and set as
+// Config.Policy so ValidateAddress applies them to every result
+type Policy struct {
+	Name string `yaml:"name" json:"name"`
+
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule fires when a result matches When and doesn't match Unless; either can be left out
+type Rule struct {
+	Name string `yaml:"name" json:"name"`
+
+	When   *Condition `yaml:"when,omitempty" json:"when,omitempty"`
+	Unless *Condition `yaml:"unless,omitempty" json:"unless,omitempty"`
+
+	// What firing the rule means: warn or reject
+	Action Decision `yaml:"action" json:"action"`
+
+	// Message to show the user when the rule fires
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// Condition matches a validation result when every field that is set matches
+// A list matches if any of its values does
+type Condition struct {
+	DPV []DPV `yaml:"dpv,omitempty" json:"dpv,omitempty"`
+
+	CMRA                 Flag `yaml:"cmra,omitempty" json:"cmra,omitempty"`
+	Vacant               Flag `yaml:"vacant,omitempty" json:"vacant,omitempty"`
+	Business             Flag `yaml:"business,omitempty" json:"business,omitempty"`
+	CentralDeliveryPoint Flag `yaml:"centralDeliveryPoint,omitempty" json:"centralDeliveryPoint,omitempty"`
+
+	// Correction codes, such as "22" for more than one match
+	Corrections []string `yaml:"corrections,omitempty" json:"corrections,omitempty"`
+
+	// Carrier routes, such as "R777" for addresses without street delivery
+	CarrierRoutes []string `yaml:"carrierRoutes,omitempty" json:"carrierRoutes,omitempty"`
+
+	// Whether the address is an APO/FPO/DPO military address
+	Military *bool `yaml:"military,omitempty" json:"military,omitempty"`
+}
+
+// PolicyResult is the decision a Policy made about a validation result
+type PolicyResult struct {
+	Decision Decision
+
+	// The rules that fired, in the order they appear in the policy
+	Fired []Rule
+}
+
+// ParsePolicy reads a policy from YAML or JSON
+// Unknown keys are an error, so a misspelled condition doesn't silently match everything
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := decodePolicy(data, &policy); err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// LoadPolicy reads a policy from a YAML or JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicies reads the policies of several services from one YAML or JSON document with a
+// top-level "policies" list, and returns them by name
+func ParsePolicies(data []byte) (map[string]*Policy, error) {
+	var doc struct {
+		Policies []*Policy `yaml:"policies"`
+	}
+	if err := decodePolicy(data, &doc); err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*Policy, len(doc.Policies))
+	for i, policy := range doc.Policies {
+		if policy == nil {
+			return nil, fmt.Errorf("policy %d is empty", i+1)
+		}
+		if policy.Name == "" {
+			return nil, fmt.Errorf("policy %d: name is required", i+1)
+		}
+		if _, ok := policies[policy.Name]; ok {
+			return nil, fmt.Errorf("policy %q is defined more than once", policy.Name)
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("policy %q: %w", policy.Name, err)
+		}
+		policies[policy.Name] = policy
+	}
+	return policies, nil
+}
+
+// LoadPolicies reads the policies of several services from a YAML or JSON file (see ParsePolicies)
+func LoadPolicies(path string) (map[string]*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+	return ParsePolicies(data)
+}
+
+// decodePolicy decodes YAML or JSON, which is also YAML, rejecting unknown keys
+func decodePolicy(data []byte, v any) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse policy: %w", err)
+	}
+	return nil
+}
+
+// policyDPVs are the DPV indicators a condition can match
+var policyDPVs = []DPV{DPVConfirmed, DPVMissingSecondary, DPVSecondaryUnconfirmed, DPVNotConfirmed}
+
+// Validate checks that every rule has a name and a warn or reject action, and that its conditions
+// only use values USPS returns, so a rule can't silently never fire
+func (p *Policy) Validate() error {
+	var errs []error
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("policy rule %d: name is required", i+1))
+		}
+		if rule.Action != DecisionWarn && rule.Action != DecisionReject {
+			errs = append(errs, fmt.Errorf("policy rule %d (%s): action must be warn or reject", i+1, rule.Name))
+		}
+		for _, c := range []*Condition{rule.When, rule.Unless} {
+			if c == nil {
+				continue
+			}
+			for _, err := range c.validate() {
+				errs = append(errs, fmt.Errorf("policy rule %d (%s): %w", i+1, rule.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate returns an error for every value in the condition that can't match a result
+func (c Condition) validate() []error {
+	var errs []error
+	for _, dpv := range c.DPV {
+		if !slices.Contains(policyDPVs, DPV(strings.ToUpper(string(dpv)))) {
+			errs = append(errs, fmt.Errorf("dpv must be Y, D, S or N, not %q", dpv))
+		}
+	}
+
+	flags := []struct {
+		name  string
+		value Flag
+	}{
+		{"cmra", c.CMRA},
+		{"vacant", c.Vacant},
+		{"business", c.Business},
+		{"centralDeliveryPoint", c.CentralDeliveryPoint},
+	}
+	for _, f := range flags {
+		switch Flag(strings.ToUpper(string(f.value))) {
+		case FlagUnknown, FlagYes, FlagNo:
+		default:
+			errs = append(errs, fmt.Errorf("%s must be Y or N, not %q", f.name, f.value))
+		}
+	}
+	return errs
+}
+
+// Evaluate applies the policy to a validation result
+// The decision is the most severe action of the rules that fired, or accept if none did
+func (p *Policy) Evaluate(result ValidationResult) PolicyResult {
+	var r PolicyResult
+	for _, rule := range p.Rules {
+		if !rule.fires(result) {
+			continue
+		}
+		r.Fired = append(r.Fired, rule)
+		r.Decision = max(r.Decision, rule.Action)
+	}
+	return r
+}
+
+// fires reports whether the rule applies to a result
+func (rule Rule) fires(result ValidationResult) bool {
+	if rule.When != nil && !rule.When.matches(result) {
+		return false
+	}
+	if rule.Unless != nil && rule.Unless.matches(result) {
+		return false
+	}
+	return true
+}
+
+// matches reports whether every field set in the condition matches the result
+func (c Condition) matches(result ValidationResult) bool {
+	var info AdditionalInfo
+	if result.AdditionalInfo != nil {
+		info = *result.AdditionalInfo
+	}
+
+	if len(c.DPV) > 0 && !slices.ContainsFunc(c.DPV, func(dpv DPV) bool { return strings.EqualFold(string(dpv), string(info.DPVConfirmation)) }) {
+		return false
+	}
+
+	flags := []struct{ want, got Flag }{
+		{c.CMRA, info.DPVCMRA},
+		{c.Vacant, info.Vacant},
+		{c.Business, info.Business},
+		{c.CentralDeliveryPoint, info.CentralDeliveryPoint},
+	}
+	for _, f := range flags {
+		if f.want != "" && !strings.EqualFold(string(f.want), string(f.got)) {
+			return false
+		}
+	}
+
+	if len(c.Corrections) > 0 && !slices.ContainsFunc(c.Corrections, func(code string) bool { return hasCorrection(result, code) }) {
+		return false
+	}
+
+	if len(c.CarrierRoutes) > 0 && !slices.ContainsFunc(c.CarrierRoutes, func(route string) bool { return strings.EqualFold(route, info.CarrierRoute) }) {
+		return false
+	}
+
+	if c.Military != nil && *c.Military != result.Military {
+		return false
+	}
+
+	return true
+}
+
+// PolicyError is returned by ValidateAddress when Config.Policy rejects every result
+// It matches ErrRejectedByPolicy
+type PolicyError struct {
+	// Name of the policy
+	Policy string
+
+	// The rejected results, with the rules that fired in each one's Policy field
+	Results []ValidationResult
+}
+
+func (e *PolicyError) Error() string {
+	var names []string
+	for _, result := range e.Results {
+		for _, rule := range result.Policy.Fired {
+			if rule.Action == DecisionReject && !slices.Contains(names, rule.Name) {
+				names = append(names, rule.Name)
+			}
+		}
+	}
+	policy := "policy"
+	if e.Policy != "" {
+		policy = fmt.Sprintf("policy %q", e.Policy)
+	}
+	return fmt.Sprintf("address rejected by %s: %s", policy, strings.Join(names, ", "))
+}
+
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrRejectedByPolicy
+}
+
+// applyPolicy evaluates the policy against each result and drops the rejected ones
+// Returns a *PolicyError if every result was rejected
+func applyPolicy(policy *Policy, results []ValidationResult) ([]ValidationResult, error) {
+	var accepted, rejected []ValidationResult
+	for _, result := range results {
+		evaluation := policy.Evaluate(result)
+		result.Policy = &evaluation
+		if evaluation.Decision == DecisionReject {
+			rejected = append(rejected, result)
+			continue
+		}
+		accepted = append(accepted, result)
+	}
+
+	if len(accepted) == 0 && len(rejected) > 0 {
+		return nil, &PolicyError{Policy: policy.Name, Results: rejected}
+	}
+	return accepted, nil
+}
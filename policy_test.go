@@ -0,0 +1,162 @@
+package uspsaddr
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	const policyYAML = `
+name: shipping
+rules:
+  - name: vacant
+    when: {vacant: yes}
+    action: reject
+  - name: not-deliverable
+    when: {dpv: [N, d]}
+    action: reject
+  - name: cmra
+    when: {cmra: true}
+    unless: {business: "Y"}
+    action: warn
+  - name: no-street-delivery
+    when: {carrierRoutes: [R777]}
+    action: warn
+`
+	policy, err := ParsePolicy([]byte(policyYAML))
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		info  AdditionalInfo
+		want  Decision
+		fired []string
+	}{
+		{
+			name: "confirmed",
+			info: AdditionalInfo{DPVConfirmation: DPVConfirmed, Vacant: FlagNo, DPVCMRA: FlagNo},
+			want: DecisionAccept,
+		},
+		{
+			name:  "vacant",
+			info:  AdditionalInfo{DPVConfirmation: DPVConfirmed, Vacant: FlagYes},
+			want:  DecisionReject,
+			fired: []string{"vacant"},
+		},
+		{
+			name:  "missing secondary, lower-case in policy",
+			info:  AdditionalInfo{DPVConfirmation: DPVMissingSecondary},
+			want:  DecisionReject,
+			fired: []string{"not-deliverable"},
+		},
+		{
+			name:  "residential CMRA",
+			info:  AdditionalInfo{DPVConfirmation: DPVConfirmed, DPVCMRA: FlagYes, Business: FlagNo},
+			want:  DecisionWarn,
+			fired: []string{"cmra"},
+		},
+		{
+			name: "business CMRA",
+			info: AdditionalInfo{DPVConfirmation: DPVConfirmed, DPVCMRA: FlagYes, Business: FlagYes},
+			want: DecisionAccept,
+		},
+		{
+			name:  "warn and reject",
+			info:  AdditionalInfo{DPVConfirmation: DPVConfirmed, Vacant: FlagYes, CarrierRoute: "R777"},
+			want:  DecisionReject,
+			fired: []string{"vacant", "no-street-delivery"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := policy.Evaluate(ValidationResult{AdditionalInfo: &tt.info})
+			var fired []string
+			for _, rule := range result.Fired {
+				fired = append(fired, rule.Name)
+			}
+			if result.Decision != tt.want || !slices.Equal(fired, tt.fired) {
+				t.Errorf("Evaluate() = %v %v, want %v %v", result.Decision, fired, tt.want, tt.fired)
+			}
+		})
+	}
+}
+
+func TestParsePolicyFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    Flag
+		wantErr bool
+	}{
+		{name: "Y", policy: `{"rules": [{"name": "r", "when": {"vacant": "Y"}, "action": "reject"}]}`, want: FlagYes},
+		{name: "JSON true", policy: `{"rules": [{"name": "r", "when": {"vacant": true}, "action": "reject"}]}`, want: FlagYes},
+		{name: "JSON false", policy: `{"rules": [{"name": "r", "when": {"vacant": false}, "action": "reject"}]}`, want: FlagNo},
+		{name: "yes", policy: "rules: [{name: r, when: {vacant: yes}, action: reject}]", want: FlagYes},
+		{name: "no", policy: "rules: [{name: r, when: {vacant: no}, action: reject}]", want: FlagNo},
+		{name: "lower-case n", policy: "rules: [{name: r, when: {vacant: n}, action: reject}]", want: FlagNo},
+		{name: "unknown flag", policy: "rules: [{name: r, when: {vacant: maybe}, action: reject}]", wantErr: true},
+		{name: "unknown flag in unless", policy: "rules: [{name: r, unless: {cmra: 1}, action: reject}]", wantErr: true},
+		{name: "unknown DPV", policy: "rules: [{name: r, when: {dpv: [Q]}, action: reject}]", wantErr: true},
+		{name: "empty DPV", policy: `rules: [{name: r, when: {dpv: [""]}, action: reject}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(tt.policy))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParsePolicy() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePolicy() error = %v", err)
+			}
+			if got := policy.Rules[0].When.Vacant; got != tt.want {
+				t.Errorf("Vacant = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		policies  string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "two policies",
+			policies:  "policies:\n  - name: checkout\n    rules: [{name: vacant, when: {vacant: Y}, action: reject}]\n  - name: billing\n",
+			wantNames: []string{"billing", "checkout"},
+		},
+		{name: "JSON null entry", policies: `{"policies": [null]}`, wantErr: true},
+		{name: "bare YAML item", policies: "policies:\n  -\n  - name: checkout\n", wantErr: true},
+		{name: "missing name", policies: "policies:\n  - rules: []\n", wantErr: true},
+		{name: "duplicate name", policies: "policies:\n  - name: a\n  - name: a\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := ParsePolicies([]byte(tt.policies))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("ParsePolicies() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePolicies() error = %v", err)
+			}
+			names := slices.Sorted(maps.Keys(policies))
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("ParsePolicies() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
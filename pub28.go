@@ -0,0 +1,116 @@
+package uspsaddr
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// USPS Publication 28 (Postal Addressing Standards) tables
+// See https://pe.usps.com/text/pub28/28apc_002.htm
+
+//go:embed data/street_suffixes.csv
+var streetSuffixData string
+
+//go:embed data/secondary_units.csv
+var secondaryUnitData string
+
+//go:embed data/directionals.csv
+var directionalData string
+
+//go:embed data/spanish_street_types.csv
+var spanishStreetTypeData string
+
+// streetSuffixes maps every known spelling of a street suffix to its standard abbreviation
+var streetSuffixes = func() map[string]string {
+	suffixes := map[string]string{}
+	for _, fields := range parseTable(streetSuffixData) {
+		for _, name := range fields {
+			suffixes[name] = fields[0]
+		}
+	}
+	return suffixes
+}()
+
+// secondaryUnit describes a secondary unit designator
+type secondaryUnit struct {
+	// Standard abbreviation, e.g. APT
+	Abbreviation string
+
+	// Whether the designator must be followed by a unit number (APT 15) or stands alone (REAR)
+	RequiresRange bool
+}
+
+// secondaryUnits maps every known spelling of a secondary unit designator to its description
+var secondaryUnits = func() map[string]secondaryUnit {
+	units := map[string]secondaryUnit{}
+	for _, fields := range parseTable(secondaryUnitData) {
+		unit := secondaryUnit{
+			Abbreviation:  fields[0],
+			RequiresRange: fields[1] == "range",
+		}
+		units[fields[0]] = unit
+		for _, name := range fields[2:] {
+			units[name] = unit
+		}
+	}
+	return units
+}()
+
+// directionals maps every known spelling of a directional to its standard abbreviation
+var directionals = func() map[string]string {
+	dirs := map[string]string{}
+	for _, fields := range parseTable(directionalData) {
+		for _, name := range fields {
+			dirs[name] = fields[0]
+		}
+	}
+	return dirs
+}()
+
+// spanishStreetTypes maps every known spelling of a Spanish street type to its standard abbreviation
+var spanishStreetTypes = func() map[string]string {
+	types := map[string]string{}
+	for _, fields := range parseTable(spanishStreetTypeData) {
+		for _, name := range fields {
+			types[name] = fields[0]
+		}
+	}
+	return types
+}()
+
+// parseTable splits an embedded comma separated table into rows, skipping comments and blank lines
+func parseTable(data string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rows = append(rows, strings.Split(line, ","))
+	}
+	return rows
+}
+
+// streetSuffix returns the standard abbreviation if the word is a street suffix
+func streetSuffix(word string) (string, bool) {
+	abbr, ok := streetSuffixes[strings.ToUpper(word)]
+	return abbr, ok
+}
+
+// lookupSecondaryUnit returns the designator if the word is a secondary unit designator
+func lookupSecondaryUnit(word string) (secondaryUnit, bool) {
+	unit, ok := secondaryUnits[strings.ToUpper(word)]
+	return unit, ok
+}
+
+// directional returns the standard abbreviation if the word is a directional
+func directional(word string) (string, bool) {
+	abbr, ok := directionals[strings.ToUpper(word)]
+	return abbr, ok
+}
+
+// spanishStreetType returns the standard abbreviation if the word is a Spanish street type
+func spanishStreetType(word string) (string, bool) {
+	abbr, ok := spanishStreetTypes[strings.ToUpper(word)]
+	return abbr, ok
+}
@@ -0,0 +1,152 @@
+package uspsaddr
+
+import (
+	"strings"
+	"unicode"
+)
+
+// puertoRicoZIPPrefixes are the ZIP3 prefixes assigned to Puerto Rico
+// 008 sits between them but belongs to the US Virgin Islands
+var puertoRicoZIPPrefixes = map[string]bool{
+	"006": true,
+	"007": true,
+	"009": true,
+}
+
+// urbanizationWarning is added to a result when USPS couldn't pick between several
+// Puerto Rico addresses and no urbanization was given to tell them apart
+const urbanizationWarning = "Puerto Rico address was sent without an urbanization and USPS found more than one match; " +
+	"adding the urbanization (URB) should resolve it"
+
+// IsPuertoRico reports whether the address is in Puerto Rico, going by its state or ZIP code
+func (a Address) IsPuertoRico() bool {
+	if a.State != "" {
+		return strings.EqualFold(a.State, "PR")
+	}
+	return len(a.ZIPCode) >= 3 && puertoRicoZIPPrefixes[a.ZIPCode[:3]]
+}
+
+// preparePuertoRico moves an urbanization ("URB LAS GLADIOLAS") given as part of the
+// street, secondary or city line into the Urbanization field, and fills in a missing state
+func preparePuertoRico(address *Address) {
+	if address.State == "" {
+		address.State = "PR"
+	}
+
+	if address.Urbanization != "" {
+		address.Urbanization = standardizeUrbanization(strings.Fields(address.Urbanization))
+		return
+	}
+
+	// A line of its own: "Urb Las Gladiolas" as the second address line
+	if words := strings.Fields(address.SecondaryAddress); isUrbanizationWord(words) && len(words) > 1 {
+		address.Urbanization = standardizeUrbanization(words)
+		address.SecondaryAddress = ""
+		return
+	}
+
+	if street, urbanization := splitUrbanizationLine(strings.Fields(address.StreetAddress)); urbanization != "" {
+		address.StreetAddress = strings.Join(street, " ")
+		address.Urbanization = urbanization
+		return
+	}
+
+	// "Urb Las Gladiolas" in place of the city, or "San Juan Urb Las Gladiolas"
+	words := strings.Fields(address.City)
+	for i := range words {
+		if isUrbanizationWord(words[i:]) && i+1 < len(words) {
+			address.City = strings.Join(words[:i], " ")
+			address.Urbanization = standardizeUrbanization(words[i:])
+			return
+		}
+	}
+}
+
+// splitUrbanizationLine splits an urbanization out of a street line
+// The urbanization either comes first and runs up to the house number
+// ("URB LAS GLADIOLAS 150 CALLE A"), or comes last ("150 CALLE A URB LAS GLADIOLAS")
+// A secondary unit after a trailing urbanization stays with the street
+func splitUrbanizationLine(words []string) (street []string, urbanization string) {
+	if isUrbanizationWord(words) {
+		for i := 2; i < len(words); i++ {
+			if startsWithDigit(words[i:]) {
+				return words[i:], standardizeUrbanization(words[:i])
+			}
+		}
+		return words, ""
+	}
+
+	// Start at 2 so the house number and street name come first
+	for i := 2; i < len(words)-1; i++ {
+		if !isUrbanizationWord(words[i:]) {
+			continue
+		}
+		name, secondary := splitSecondaryTokens(words[i:])
+		if len(name) < 2 {
+			return words, ""
+		}
+		street = append(append([]string{}, words[:i]...), secondary...)
+		return street, standardizeUrbanization(name)
+	}
+
+	return words, ""
+}
+
+// isUrbanizationWord reports whether the first word introduces an urbanization (URB, Urbanización)
+func isUrbanizationWord(words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	abbr, ok := spanishStreetType(words[0])
+	return ok && abbr == "URB"
+}
+
+// standardizeUrbanization upper-cases an urbanization name and abbreviates its URB prefix
+func standardizeUrbanization(words []string) string {
+	words = append([]string{}, words...)
+	for i := range words {
+		words[i] = strings.ToUpper(words[i])
+	}
+	if isUrbanizationWord(words) {
+		words[0] = "URB"
+	}
+	return strings.Join(words, " ")
+}
+
+// standardizeSpanishStreet abbreviates the Spanish street type that comes before the
+// street name in a Puerto Rico address ("150 AVENIDA PONCE DE LEON" becomes "150 AVE PONCE DE LEON")
+func standardizeSpanishStreet(street string) string {
+	words := strings.Fields(strings.ToUpper(street))
+
+	// The street type follows the house number, or starts the line ("CONDOMINIO EL SENORIAL")
+	i := 0
+	if len(words) > 0 && unicode.IsDigit(rune(words[0][0])) {
+		i = 1
+	}
+	if i+1 < len(words) {
+		if abbr, ok := spanishStreetType(words[i]); ok {
+			words[i] = abbr
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// hasSpanishStreetType reports whether a street line has a Spanish street type after its house number
+func hasSpanishStreetType(words []string) bool {
+	if len(words) < 3 {
+		return false
+	}
+	abbr, ok := spanishStreetType(words[1])
+	return ok && abbr != "URB"
+}
+
+// hasCorrection reports whether USPS returned the given correction code
+func hasCorrection(result ValidationResult, code string) bool {
+	for _, c := range result.Corrections {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package uspsaddr
+
+import "testing"
+
+func TestPreparePuertoRico(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Address
+		want  Address
+	}{
+		{
+			name:  "urbanization as the second line",
+			input: Address{StreetAddress: "150 Calle Luna", SecondaryAddress: "Urb Las Gladiolas", City: "San Juan", State: "PR"},
+			want:  Address{StreetAddress: "150 Calle Luna", City: "San Juan", State: "PR", Urbanization: "URB LAS GLADIOLAS"},
+		},
+		{
+			name:  "urbanization before the house number, state from the ZIP code",
+			input: Address{StreetAddress: "Urb Las Gladiolas 150 Calle A", City: "San Juan", ZIPCode: "00926"},
+			want:  Address{StreetAddress: "150 Calle A", City: "San Juan", State: "PR", ZIPCode: "00926", Urbanization: "URB LAS GLADIOLAS"},
+		},
+		{
+			name:  "urbanization after the street keeps the unit on the street",
+			input: Address{StreetAddress: "150 Calle A Urb Las Gladiolas Apt 2", City: "San Juan", State: "PR"},
+			want:  Address{StreetAddress: "150 Calle A Apt 2", City: "San Juan", State: "PR", Urbanization: "URB LAS GLADIOLAS"},
+		},
+		{
+			name:  "urbanization after the city",
+			input: Address{StreetAddress: "150 Calle A", City: "San Juan Urb Las Gladiolas", State: "PR"},
+			want:  Address{StreetAddress: "150 Calle A", City: "San Juan", State: "PR", Urbanization: "URB LAS GLADIOLAS"},
+		},
+		{
+			name:  "Spanish spelling",
+			input: Address{StreetAddress: "150 Calle A", City: "Carolina Urbanización Villa Carolina", State: "PR"},
+			want:  Address{StreetAddress: "150 Calle A", City: "Carolina", State: "PR", Urbanization: "URB VILLA CAROLINA"},
+		},
+		{
+			name:  "urbanization field standardized",
+			input: Address{StreetAddress: "150 Calle A", City: "San Juan", State: "PR", Urbanization: "urb las gladiolas"},
+			want:  Address{StreetAddress: "150 Calle A", City: "San Juan", State: "PR", Urbanization: "URB LAS GLADIOLAS"},
+		},
+		{
+			name:  "URB as a street name",
+			input: Address{StreetAddress: "150 Calle Urb", City: "San Juan", State: "PR"},
+			want:  Address{StreetAddress: "150 Calle Urb", City: "San Juan", State: "PR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.input
+			preparePuertoRico(&address)
+			if address != tt.want {
+				t.Errorf("preparePuertoRico() = %+v\nwant %+v", address, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPuertoRico(t *testing.T) {
+	tests := []struct {
+		address Address
+		want    bool
+	}{
+		{Address{State: "PR"}, true},
+		{Address{State: "pr", ZIPCode: "80302"}, true},
+		{Address{ZIPCode: "00926"}, true},
+		{Address{ZIPCode: "00983"}, true},
+		{Address{ZIPCode: "00820"}, false}, // US Virgin Islands
+		{Address{State: "VI", ZIPCode: "00926"}, false},
+		{Address{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.address.IsPuertoRico(); got != tt.want {
+			t.Errorf("%+v IsPuertoRico() = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddressUrbanization(t *testing.T) {
+	got, err := ParseAddress("150 Calle Luna, Urb Las Gladiolas, San Juan, PR 00926")
+	if err != nil {
+		t.Fatalf("ParseAddress() error = %v", err)
+	}
+	want := Address{StreetAddress: "150 Calle Luna", City: "San Juan", State: "PR", ZIPCode: "00926", Urbanization: "URB LAS GLADIOLAS"}
+	if *got != want {
+		t.Errorf("ParseAddress() = %+v\nwant %+v", *got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Region is the shipping region an address is in
+type Region int
+
+const (
+	// RegionUnknown means the state and ZIP code weren't recognized
+	RegionUnknown Region = iota
+
+	// RegionCONUS is the contiguous United States: the lower 48 states and the District of Columbia
+	RegionCONUS
+
+	// RegionAlaska is Alaska
+	RegionAlaska
+
+	// RegionHawaii is Hawaii
+	RegionHawaii
+
+	// RegionTerritory is a US territory (PR, GU, VI, AS, MP)
+	RegionTerritory
+
+	// RegionFreelyAssociated is a freely associated state served by USPS (FM, MH, PW)
+	RegionFreelyAssociated
+
+	// RegionMilitary is an Armed Forces region for APO/FPO/DPO mail (AA, AE, AP)
+	RegionMilitary
+)
+
+func (r Region) String() string {
+	switch r {
+	case RegionUnknown:
+		return "unknown"
+	case RegionCONUS:
+		return "CONUS"
+	case RegionAlaska:
+		return "Alaska"
+	case RegionHawaii:
+		return "Hawaii"
+	case RegionTerritory:
+		return "territory"
+	case RegionFreelyAssociated:
+		return "freely associated state"
+	case RegionMilitary:
+		return "military"
+	}
+	return fmt.Sprintf("Region(%d)", int(r))
+}
+
+// IsCONUS reports whether the region is the contiguous United States
+func (r Region) IsCONUS() bool {
+	return r == RegionCONUS
+}
+
+// IsNoncontiguous reports whether the region is a non-contiguous domestic destination:
+// Alaska, Hawaii, a territory or a freely associated state
+func (r Region) IsNoncontiguous() bool {
+	switch r {
+	case RegionAlaska, RegionHawaii, RegionTerritory, RegionFreelyAssociated:
+		return true
+	}
+	return false
+}
+
+// IsMilitary reports whether the region is an Armed Forces region
+func (r Region) IsMilitary() bool {
+	return r == RegionMilitary
+}
+
+// Region returns the shipping region of the address, going by its state, or its ZIP code if
+// the state is missing or not recognized
+func (a Address) Region() Region {
+	info, ok := stateByCode[strings.ToUpper(a.State)]
+	if !ok {
+		if info, ok = StateForZIP(a.ZIPCode); !ok {
+			return RegionUnknown
+		}
+	}
+
+	switch info.Category {
+	case StateCategoryTerritory:
+		return RegionTerritory
+	case StateCategoryFreelyAssociated:
+		return RegionFreelyAssociated
+	case StateCategoryMilitary:
+		return RegionMilitary
+	}
+
+	switch info.Code {
+	case "AK":
+		return RegionAlaska
+	case "HI":
+		return RegionHawaii
+	}
+	return RegionCONUS
+}
+
+// Region returns the shipping region of the canonical address, falling back to the input
+// if USPS didn't return a state or ZIP code
+func (r ValidationResult) Region() Region {
+	if region := r.Address.Region(); region != RegionUnknown {
+		return region
+	}
+	return r.Input.Region()
+}
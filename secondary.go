@@ -0,0 +1,47 @@
+package uspsaddr
+
+import "strings"
+
+// SecondarySplit records a secondary unit that was moved out of the street address
+type SecondarySplit struct {
+	// The street address as it was given
+	Original string
+
+	// The street address and secondary unit that were sent to USPS
+	StreetAddress    string
+	SecondaryAddress string
+
+	// The standard abbreviation of the designator that was recognized, such as "APT" or "#"
+	Designator string
+}
+
+// splitSecondary moves a secondary unit ("Apt 15", "#15", "Rear") from the end of the
+// street address into SecondaryAddress
+// Returns nil if the address already has a secondary address or none was found
+func splitSecondary(address *Address) *SecondarySplit {
+	if address.SecondaryAddress != "" {
+		return nil
+	}
+
+	street, secondary := splitSecondaryTokens(strings.Fields(address.StreetAddress))
+	if len(secondary) == 0 {
+		return nil
+	}
+
+	designator := "#"
+	if unit, ok := lookupSecondaryUnit(secondary[0]); ok {
+		designator = unit.Abbreviation
+	}
+
+	split := &SecondarySplit{
+		Original:         address.StreetAddress,
+		StreetAddress:    strings.Join(street, " "),
+		SecondaryAddress: strings.Join(secondary, " "),
+		Designator:       designator,
+	}
+
+	address.StreetAddress = split.StreetAddress
+	address.SecondaryAddress = split.SecondaryAddress
+
+	return split
+}
@@ -0,0 +1,48 @@
+package uspsaddr
+
+import "testing"
+
+func TestSplitSecondary(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     Address
+		street    string
+		secondary string
+		// Designator of the split, or "" if nothing should be split
+		designator string
+	}{
+		{name: "apartment", input: Address{StreetAddress: "1820 Mary Apt 15"}, street: "1820 Mary", secondary: "Apt 15", designator: "APT"},
+		{name: "pound sign", input: Address{StreetAddress: "1820 Mary #15"}, street: "1820 Mary", secondary: "#15", designator: "#"},
+		{name: "pound sign and space", input: Address{StreetAddress: "1820 Mary # 15"}, street: "1820 Mary", secondary: "# 15", designator: "#"},
+		{name: "suite after suffix", input: Address{StreetAddress: "500 Elm St Suite 200B"}, street: "500 Elm St", secondary: "Suite 200B", designator: "STE"},
+		{name: "letter unit", input: Address{StreetAddress: "500 Elm St Ste A"}, street: "500 Elm St", secondary: "Ste A", designator: "STE"},
+		{name: "floor and suite", input: Address{StreetAddress: "100 Main St Fl 3 Ste 300"}, street: "100 Main St", secondary: "Fl 3 Ste 300", designator: "FL"},
+		{name: "designator without a number", input: Address{StreetAddress: "12 Oak Ave Rear"}, street: "12 Oak Ave", secondary: "Rear", designator: "REAR"},
+		{name: "designator as street name", input: Address{StreetAddress: "12 Rear Ave"}, street: "12 Rear Ave"},
+		{name: "suite as street name", input: Address{StreetAddress: "100 Suite St"}, street: "100 Suite St"},
+		{name: "unit as street name", input: Address{StreetAddress: "100 Unit Rd"}, street: "100 Unit Rd"},
+		{name: "designator missing its number", input: Address{StreetAddress: "100 Main St Apt"}, street: "100 Main St Apt"},
+		{name: "secondary already given", input: Address{StreetAddress: "1820 Mary Apt 15", SecondaryAddress: "Unit 2"}, street: "1820 Mary Apt 15", secondary: "Unit 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.input
+			split := splitSecondary(&address)
+			if address.StreetAddress != tt.street || address.SecondaryAddress != tt.secondary {
+				t.Errorf("splitSecondary() = %q / %q, want %q / %q", address.StreetAddress, address.SecondaryAddress, tt.street, tt.secondary)
+			}
+
+			if tt.designator == "" {
+				if split != nil {
+					t.Errorf("splitSecondary() returned %+v, want nil", split)
+				}
+				return
+			}
+			want := SecondarySplit{Original: tt.input.StreetAddress, StreetAddress: tt.street, SecondaryAddress: tt.secondary, Designator: tt.designator}
+			if split == nil || *split != want {
+				t.Errorf("splitSecondary() returned %+v, want %+v", split, want)
+			}
+		})
+	}
+}
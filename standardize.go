@@ -0,0 +1,98 @@
+package uspsaddr
+
+import "strings"
+
+// Standardize formats an address according to USPS Publication 28 without calling USPS
+// Street suffixes, directionals and secondary unit designators are abbreviated, punctuation
+// is removed and everything is upper-cased, so the result can be compared with the canonical
+// addresses USPS returns (for example when deduplicating, or in tests)
+// The address is first prepared as ValidateAddress does: it is transliterated to ASCII, and
+// military unit and box lines are merged into one street line
+func Standardize(address Address) Address {
+	prepared := prepareAddress(address)
+	result := prepared.Address
+	puertoRico := prepared.PuertoRico
+
+	if result.StreetAddressAbbreviation == "" {
+		result.StreetAddressAbbreviation = result.StreetAddress
+	}
+
+	result.Firm = strings.ToUpper(result.Firm)
+	result.StreetAddress = standardizeStreet(result.StreetAddress)
+	result.StreetAddressAbbreviation = standardizeStreet(result.StreetAddressAbbreviation)
+	if puertoRico {
+		result.StreetAddress = standardizeSpanishStreet(result.StreetAddress)
+		result.StreetAddressAbbreviation = standardizeSpanishStreet(result.StreetAddressAbbreviation)
+	}
+	result.SecondaryAddress = standardizeSecondary(result.SecondaryAddress)
+	result.City = strings.ToUpper(result.City)
+	result.CityAbbreviation = strings.ToUpper(result.CityAbbreviation)
+	result.Urbanization = strings.ToUpper(result.Urbanization)
+
+	return result
+}
+
+// standardizeStreet abbreviates the directionals and suffix of a street line
+// Words are only abbreviated in their standard positions, so the street name itself is kept:
+// "100 NORTH ST" and "123 AVENUE B" are left alone, "100 North Main Street" becomes "100 N MAIN ST"
+func standardizeStreet(street string) string {
+	words := strings.Fields(strings.ToUpper(street))
+	if len(words) < 3 {
+		return strings.Join(words, " ")
+	}
+
+	// Post-directional ("1600 PENNSYLVANIA AVE NW")
+	last := len(words) - 1
+	if abbr, ok := directional(words[last]); ok {
+		words[last] = abbr
+		last--
+	}
+
+	// Suffix follows the house number and street name
+	nameEnd := last + 1
+	if last >= 2 {
+		if abbr, ok := streetSuffix(words[last]); ok {
+			words[last] = abbr
+			nameEnd = last
+		}
+	}
+
+	// Pre-directional follows the house number, and must leave a street name after it
+	if nameEnd > 2 {
+		if abbr, ok := directional(words[1]); ok {
+			words[1] = abbr
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// standardizeSecondary abbreviates the designator of a secondary unit ("Suite 200" becomes "STE 200")
+func standardizeSecondary(secondary string) string {
+	words := strings.Fields(strings.ToUpper(secondary))
+	if len(words) == 0 {
+		return ""
+	}
+
+	if number, ok := strings.CutPrefix(words[0], "#"); ok {
+		// "#15" becomes "# 15"
+		if number != "" {
+			words = append([]string{"#", number}, words[1:]...)
+		}
+		return strings.Join(words, " ")
+	}
+
+	if unit, ok := lookupSecondaryUnit(words[0]); ok {
+		words[0] = unit.Abbreviation
+	}
+
+	// "APT #15" becomes "APT 15"
+	if len(words) > 1 {
+		words[1] = strings.TrimPrefix(words[1], "#")
+		if words[1] == "" {
+			words = append(words[:1], words[2:]...)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
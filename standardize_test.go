@@ -0,0 +1,50 @@
+package uspsaddr
+
+import "testing"
+
+func TestStandardize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Address
+		want  Address
+	}{
+		{
+			name:  "suffix and directionals",
+			input: Address{StreetAddress: "100 North Main Street", City: "Boulder", State: "Colorado"},
+			want:  Address{StreetAddress: "100 N MAIN ST", StreetAddressAbbreviation: "100 N MAIN ST", City: "BOULDER", State: "CO"},
+		},
+		{
+			name:  "street names that look like suffixes and directionals",
+			input: Address{StreetAddress: "123 Avenue B", City: "New York", State: "NY"},
+			want:  Address{StreetAddress: "123 AVENUE B", StreetAddressAbbreviation: "123 AVENUE B", City: "NEW YORK", State: "NY"},
+		},
+		{
+			name:  "post-directional",
+			input: Address{StreetAddress: "1600 Pennsylvania Avenue Northwest", City: "Washington", State: "DC"},
+			want:  Address{StreetAddress: "1600 PENNSYLVANIA AVE NW", StreetAddressAbbreviation: "1600 PENNSYLVANIA AVE NW", City: "WASHINGTON", State: "DC"},
+		},
+		{
+			name:  "secondary split off and abbreviated",
+			input: Address{StreetAddress: "500 Elm St Suite 200", City: "Boulder", State: "CO"},
+			want:  Address{StreetAddress: "500 ELM ST", StreetAddressAbbreviation: "500 ELM ST", SecondaryAddress: "STE 200", City: "BOULDER", State: "CO"},
+		},
+		{
+			name:  "fraction and accents transliterated",
+			input: Address{StreetAddress: "123½ Main St", City: "Peñasco", State: "NM"},
+			want:  Address{StreetAddress: "123 1/2 MAIN ST", StreetAddressAbbreviation: "123 1/2 MAIN ST", City: "PENASCO", State: "NM"},
+		},
+		{
+			name:  "military box merged",
+			input: Address{StreetAddress: "psc 1234", SecondaryAddress: "Box 5678", City: "apo", State: "AE", ZIPCode: "09001"},
+			want:  Address{StreetAddress: "PSC 1234 BOX 5678", StreetAddressAbbreviation: "PSC 1234 BOX 5678", City: "APO", State: "AE", ZIPCode: "09001"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Standardize(tt.input); got != tt.want {
+				t.Errorf("Standardize() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
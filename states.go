@@ -0,0 +1,338 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// StateCategory classifies the places USPS accepts as a state
+type StateCategory int
+
+const (
+	// StateCategoryState is one of the 50 states or the District of Columbia
+	StateCategoryState StateCategory = iota + 1
+
+	// StateCategoryTerritory is a US territory (PR, GU, VI, AS, MP)
+	StateCategoryTerritory
+
+	// StateCategoryFreelyAssociated is a freely associated state served by USPS (FM, MH, PW)
+	StateCategoryFreelyAssociated
+
+	// StateCategoryMilitary is an Armed Forces region used for APO/FPO/DPO mail (AA, AE, AP)
+	StateCategoryMilitary
+)
+
+func (c StateCategory) String() string {
+	switch c {
+	case StateCategoryState:
+		return "state"
+	case StateCategoryTerritory:
+		return "territory"
+	case StateCategoryFreelyAssociated:
+		return "freely associated state"
+	case StateCategoryMilitary:
+		return "military"
+	}
+	return fmt.Sprintf("StateCategory(%d)", int(c))
+}
+
+// StateInfo describes a state, territory or military region that USPS delivers to
+type StateInfo struct {
+	// Two-letter USPS code
+	Code string
+
+	// Full name
+	Name string
+
+	Category StateCategory
+}
+
+// InvalidStateError is returned when an address's state isn't one USPS accepts
+type InvalidStateError struct {
+	// The state as it was given
+	State string
+
+	// The code of the state the value looks like a misspelling of (see SuggestState), if any
+	SuggestedState string
+}
+
+func (e *InvalidStateError) Error() string {
+	if e.SuggestedState != "" {
+		return fmt.Sprintf("%q is not a valid USPS state code, did you mean %s?", e.State, e.SuggestedState)
+	}
+	return fmt.Sprintf("%q is not a valid USPS state code", e.State)
+}
+
+func (e *InvalidStateError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// stateRegistry lists every state code in the USPS Addresses API State pattern
+var stateRegistry = []StateInfo{
+	{"AA", "Armed Forces Americas", StateCategoryMilitary},
+	{"AE", "Armed Forces Europe", StateCategoryMilitary},
+	{"AK", "Alaska", StateCategoryState},
+	{"AL", "Alabama", StateCategoryState},
+	{"AP", "Armed Forces Pacific", StateCategoryMilitary},
+	{"AR", "Arkansas", StateCategoryState},
+	{"AS", "American Samoa", StateCategoryTerritory},
+	{"AZ", "Arizona", StateCategoryState},
+	{"CA", "California", StateCategoryState},
+	{"CO", "Colorado", StateCategoryState},
+	{"CT", "Connecticut", StateCategoryState},
+	{"DC", "District of Columbia", StateCategoryState},
+	{"DE", "Delaware", StateCategoryState},
+	{"FL", "Florida", StateCategoryState},
+	{"FM", "Federated States of Micronesia", StateCategoryFreelyAssociated},
+	{"GA", "Georgia", StateCategoryState},
+	{"GU", "Guam", StateCategoryTerritory},
+	{"HI", "Hawaii", StateCategoryState},
+	{"IA", "Iowa", StateCategoryState},
+	{"ID", "Idaho", StateCategoryState},
+	{"IL", "Illinois", StateCategoryState},
+	{"IN", "Indiana", StateCategoryState},
+	{"KS", "Kansas", StateCategoryState},
+	{"KY", "Kentucky", StateCategoryState},
+	{"LA", "Louisiana", StateCategoryState},
+	{"MA", "Massachusetts", StateCategoryState},
+	{"MD", "Maryland", StateCategoryState},
+	{"ME", "Maine", StateCategoryState},
+	{"MH", "Marshall Islands", StateCategoryFreelyAssociated},
+	{"MI", "Michigan", StateCategoryState},
+	{"MN", "Minnesota", StateCategoryState},
+	{"MO", "Missouri", StateCategoryState},
+	{"MP", "Northern Mariana Islands", StateCategoryTerritory},
+	{"MS", "Mississippi", StateCategoryState},
+	{"MT", "Montana", StateCategoryState},
+	{"NC", "North Carolina", StateCategoryState},
+	{"ND", "North Dakota", StateCategoryState},
+	{"NE", "Nebraska", StateCategoryState},
+	{"NH", "New Hampshire", StateCategoryState},
+	{"NJ", "New Jersey", StateCategoryState},
+	{"NM", "New Mexico", StateCategoryState},
+	{"NV", "Nevada", StateCategoryState},
+	{"NY", "New York", StateCategoryState},
+	{"OH", "Ohio", StateCategoryState},
+	{"OK", "Oklahoma", StateCategoryState},
+	{"OR", "Oregon", StateCategoryState},
+	{"PA", "Pennsylvania", StateCategoryState},
+	{"PR", "Puerto Rico", StateCategoryTerritory},
+	{"PW", "Palau", StateCategoryFreelyAssociated},
+	{"RI", "Rhode Island", StateCategoryState},
+	{"SC", "South Carolina", StateCategoryState},
+	{"SD", "South Dakota", StateCategoryState},
+	{"TN", "Tennessee", StateCategoryState},
+	{"TX", "Texas", StateCategoryState},
+	{"UT", "Utah", StateCategoryState},
+	{"VA", "Virginia", StateCategoryState},
+	{"VI", "Virgin Islands", StateCategoryTerritory},
+	{"VT", "Vermont", StateCategoryState},
+	{"WA", "Washington", StateCategoryState},
+	{"WI", "Wisconsin", StateCategoryState},
+	{"WV", "West Virginia", StateCategoryState},
+	{"WY", "Wyoming", StateCategoryState},
+}
+
+// stateAliases maps other common spellings (traditional abbreviations, long forms) to state codes
+// Keys are in stateKey form: upper case without periods or apostrophes
+var stateAliases = map[string]string{
+	"ALA":                                 "AL",
+	"ALAS":                                "AK",
+	"ARIZ":                                "AZ",
+	"ARK":                                 "AR",
+	"CALIF":                               "CA",
+	"COLO":                                "CO",
+	"CONN":                                "CT",
+	"DEL":                                 "DE",
+	"FLA":                                 "FL",
+	"ILL":                                 "IL",
+	"KANS":                                "KS",
+	"MASS":                                "MA",
+	"MICH":                                "MI",
+	"MINN":                                "MN",
+	"MISS":                                "MS",
+	"MONT":                                "MT",
+	"NEBR":                                "NE",
+	"NEV":                                 "NV",
+	"N CAROLINA":                          "NC",
+	"N DAK":                               "ND",
+	"N DAKOTA":                            "ND",
+	"N MEX":                               "NM",
+	"OKLA":                                "OK",
+	"OREG":                                "OR",
+	"PENNA":                               "PA",
+	"S CAROLINA":                          "SC",
+	"S DAK":                               "SD",
+	"S DAKOTA":                            "SD",
+	"TENN":                                "TN",
+	"TEX":                                 "TX",
+	"WASH":                                "WA",
+	"W VA":                                "WV",
+	"W VIRGINIA":                          "WV",
+	"WISC":                                "WI",
+	"WYO":                                 "WY",
+	"COMMONWEALTH OF PUERTO RICO":         "PR",
+	"US VIRGIN ISLANDS":                   "VI",
+	"USVI":                                "VI",
+	"VIRGIN ISLANDS OF THE UNITED STATES": "VI",
+	"NORTHERN MARIANAS":                   "MP",
+	"N MARIANA ISLANDS":                   "MP",
+	"CNMI":                                "MP",
+	"MICRONESIA":                          "FM",
+	"REPUBLIC OF PALAU":                   "PW",
+	"REPUBLIC OF THE MARSHALL ISLANDS":    "MH",
+	"ARMED FORCES AFRICA":                 "AE",
+	"ARMED FORCES CANADA":                 "AE",
+	"ARMED FORCES MIDDLE EAST":            "AE",
+}
+
+// stateByCode indexes stateRegistry by code
+var stateByCode = func() map[string]StateInfo {
+	byCode := make(map[string]StateInfo, len(stateRegistry))
+	for _, info := range stateRegistry {
+		byCode[info.Code] = info
+	}
+	return byCode
+}()
+
+// stateByName maps state names and aliases, in stateKey form, to state codes
+var stateByName = func() map[string]string {
+	byName := make(map[string]string, len(stateRegistry)+len(stateAliases))
+	for _, info := range stateRegistry {
+		byName[stateKey(info.Name)] = info.Code
+	}
+	for alias, code := range stateAliases {
+		byName[alias] = code
+	}
+	return byName
+}()
+
+// States returns every state, territory and military region USPS accepts, sorted by code
+func States() []StateInfo {
+	states := make([]StateInfo, len(stateRegistry))
+	copy(states, stateRegistry)
+	return states
+}
+
+// LookupState finds a state by its code, name or a common spelling
+// such as "CO", "colorado", "Colo.", "N. Carolina" or "Puerto Rico"
+// Misspelled names aren't matched; use SuggestState for those
+func LookupState(s string) (StateInfo, bool) {
+	if code, ok := stateCode(s); ok {
+		return stateByCode[code], true
+	}
+	return StateInfo{}, false
+}
+
+// SuggestState guesses the state a misspelled name means ("Colorodo"), for asking the user
+// It only suggests a state when the name has the same number of words and a small typo, and
+// no other state is as close; exact codes, names and spellings are returned as LookupState does
+// The result is a guess, so it isn't applied to addresses automatically
+func SuggestState(s string) (StateInfo, bool) {
+	if info, ok := LookupState(s); ok {
+		return info, true
+	}
+
+	key := stateKey(s)
+	if len(key) < 4 {
+		return StateInfo{}, false
+	}
+	words := len(strings.Fields(key))
+
+	// Fuzzy match against the full names; a word added or dropped ("W Virginia" for "Virginia")
+	// is a different name, not a typo
+	best, bestDistance, tie := "", 0, false
+	for _, info := range stateRegistry {
+		name := stateKey(info.Name)
+		if len(strings.Fields(name)) != words {
+			continue
+		}
+		d := editDistance(key, name)
+		if d > maxStateTypos(name) {
+			continue
+		}
+		switch {
+		case best == "" || d < bestDistance:
+			best, bestDistance, tie = info.Code, d, false
+		case d == bestDistance:
+			tie = true
+		}
+	}
+	if best == "" || tie {
+		return StateInfo{}, false
+	}
+
+	return stateByCode[best], true
+}
+
+// stateCode returns the 2-letter code for an exact state code, name or alias
+func stateCode(s string) (string, bool) {
+	key := stateKey(s)
+	if _, ok := stateByCode[key]; ok {
+		return key, true
+	}
+	code, ok := stateByName[key]
+	return code, ok
+}
+
+// stateKey upper-cases a state name and strips periods, apostrophes and extra whitespace
+// so that "N. Dak.", "Hawai'i" and "new  york" match their table entries
+func stateKey(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToUpper(r)
+		default:
+			return ' '
+		}
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// maxStateTypos is the edit distance tolerated when fuzzy matching a state name
+func maxStateTypos(name string) int {
+	if len(name) <= 6 {
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// stateNames returns the state names and aliases in stateKey form, longest first
+func stateNames() []string {
+	names := make([]string, 0, len(stateByName))
+	for name := range stateByName {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,97 @@
+package uspsaddr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupState(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"CO", "CO"},
+		{"colorado", "CO"},
+		{"Colo.", "CO"},
+		{"  new   york ", "NY"},
+		{"Puerto Rico", "PR"},
+		{"Hawai'i", "HI"},
+		{"N. Dak.", "ND"},
+		{"W Virginia", "WV"},
+		{"W. Virginia", "WV"},
+		{"West Virginia", "WV"},
+		{"Virginia", "VA"},
+		{"N Carolina", "NC"},
+		{"S. Carolina", "SC"},
+		{"S Dakota", "SD"},
+		{"N. Mariana Islands", "MP"},
+		{"Colorodo", ""},
+		{"Main", ""},
+		{"XX", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			info, ok := LookupState(tt.input)
+			if info.Code != tt.want || ok != (tt.want != "") {
+				t.Errorf("LookupState(%q) = %q, %v, want %q", tt.input, info.Code, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestState(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Colorodo", "CO"},
+		{"Pensylvania", "PA"},
+		{"W Virgina", ""},
+		{"Virginia West", ""},
+		{"New Virginia", ""},
+		{"Colorado", "CO"},
+		{"Kanas", "KS"},
+		{"Iowa", "IA"},
+		{"Ohi", ""},
+		{"Somewhere", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			info, ok := SuggestState(tt.input)
+			if info.Code != tt.want || ok != (tt.want != "") {
+				t.Errorf("SuggestState(%q) = %q, %v, want %q", tt.input, info.Code, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeStateNeverGuesses(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"W Virginia", "WV"},
+		{"West Virginia", "WV"},
+		{"Main", "MAIN"},
+		{"Colorodo", "COLORODO"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeAddress(Address{State: tt.input}).State; got != tt.want {
+			t.Errorf("NormalizeAddress(State: %q).State = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if err := CheckZIPState("25301", "W Virginia"); err != nil {
+		t.Errorf("CheckZIPState(25301, W Virginia) = %v, want nil", err)
+	}
+
+	err := ValidateInput(Address{StreetAddress: "1 Main St", City: "Denver", State: "Colorodo"})
+	var stateErr *InvalidStateError
+	if !errors.As(err, &stateErr) || stateErr.SuggestedState != "CO" {
+		t.Errorf("ValidateInput() = %v, want an *InvalidStateError suggesting CO", err)
+	}
+}
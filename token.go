@@ -29,6 +29,12 @@ type tokenResponse struct {
 	ExpiresIn   int    `json:"expires_in"` // seconds
 }
 
+// tokenErrorResponse is the OAuth2 error response from USPS
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
 // newTokenManager creates a new token manager
 func newTokenManager(clientID, clientSecret, tokenURL string, httpClient *http.Client) *tokenManager {
 	if httpClient == nil {
@@ -97,7 +103,7 @@ func (tm *tokenManager) refreshToken() (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("token request failed with status %d", resp.StatusCode)
+		return "", tokenError(resp)
 	}
 
 	// Parse response
@@ -120,3 +126,26 @@ func (tm *tokenManager) refreshToken() (string, error) {
 
 	return tm.accessToken, nil
 }
+
+// tokenError converts a failed token response to an *Error, so rejected credentials match
+// ErrUnauthorized and throttling or outages match ErrRateLimited and ErrServiceUnavailable
+// just like errors from the Addresses API
+func tokenError(resp *http.Response) *Error {
+	var body tokenErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	result := &Error{
+		Code:       body.Error,
+		Title:      body.Error,
+		Detail:     body.ErrorDescription,
+		HTTPStatus: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if result.Title == "" {
+		result.Title = http.StatusText(resp.StatusCode)
+	}
+	if result.Detail == "" {
+		result.Detail = fmt.Sprintf("token request failed with status %d", resp.StatusCode)
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package uspsaddr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		retryAfter string
+		want       error
+		wantRetry  time.Duration
+		wantDetail string
+	}{
+		{
+			name:       "bad credentials",
+			status:     http.StatusUnauthorized,
+			body:       `{"error": "invalid_client", "error_description": "Client authentication failed"}`,
+			want:       ErrUnauthorized,
+			wantDetail: "Client authentication failed",
+		},
+		{
+			name:       "no access",
+			status:     http.StatusForbidden,
+			want:       ErrForbidden,
+			wantDetail: "token request failed with status 403",
+		},
+		{
+			name:       "throttled",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "30",
+			want:       ErrRateLimited,
+			wantRetry:  30 * time.Second,
+			wantDetail: "token request failed with status 429",
+		},
+		{
+			name:       "outage",
+			status:     http.StatusServiceUnavailable,
+			body:       "<html>down for maintenance</html>",
+			want:       ErrServiceUnavailable,
+			wantDetail: "token request failed with status 503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			_, err := newTokenManager("id", "secret", server.URL, nil).getToken()
+			// The client's request editor wraps the error like this
+			err = fmt.Errorf("failed to get access token: %w", err)
+
+			var uspsErr *Error
+			if !errors.Is(err, tt.want) || !errors.As(err, &uspsErr) {
+				t.Fatalf("getToken() error = %v, want an *Error matching %v", err, tt.want)
+			}
+			if uspsErr.HTTPStatus != tt.status || uspsErr.RetryAfter != tt.wantRetry || uspsErr.Detail != tt.wantDetail {
+				t.Errorf("error = %+v, want status %d, Retry-After %v, detail %q", uspsErr, tt.status, tt.wantRetry, tt.wantDetail)
+			}
+		})
+	}
+}
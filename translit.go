@@ -0,0 +1,192 @@
+package uspsaddr
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//go:embed data/latin_letters.csv
+var latinLetterData string
+
+// Transliteration records a character that was replaced to make an address field plain ASCII
+type Transliteration struct {
+	Field Field
+
+	// Byte offset of the character in the original field value
+	Offset int
+
+	// The original character
+	From string
+
+	// The ASCII replacement; empty if the character was removed or has no ASCII equivalent
+	To string
+}
+
+// NonASCIIError is returned in strict mode (Config.StrictASCII) when an address contains
+// characters outside ASCII, instead of transliterating them
+// It is also returned in the default mode for characters that have no ASCII equivalent
+type NonASCIIError struct {
+	// Every non-ASCII character, with its suggested replacement
+	Characters []Transliteration
+}
+
+func (e *NonASCIIError) Error() string {
+	parts := make([]string, len(e.Characters))
+	for i, t := range e.Characters {
+		parts[i] = fmt.Sprintf("%s %q", t.Field, t.From)
+	}
+	return "address contains non-ASCII characters: " + strings.Join(parts, ", ")
+}
+
+func (e *NonASCIIError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// transliterations maps non-ASCII characters to the ASCII text USPS expects
+var transliterations = func() map[rune]string {
+	table := map[rune]string{}
+	groups := []struct {
+		From string
+		To   string
+	}{
+		{"‘’‚‛′´", "'"},
+		{"“”„‟″", `"`},
+		{"‐‑‒–—―−", "-"},
+		// No-break and other typographic spaces
+		{"\u00a0\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a\u202f\u205f\u3000", " "},
+		// Soft hyphen and zero-width characters
+		{"\u00ad\u200b\u200c\u200d\u2060\ufeff", ""},
+		{"º", "o"}, {"ª", "a"},
+		{"№", "No"},
+	}
+	for _, g := range groups {
+		for _, r := range g.From {
+			table[r] = g.To
+		}
+	}
+
+	for r, s := range map[rune]string{
+		'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'ß': "ss", 'Þ': "TH", 'þ': "th",
+		'½': "1/2", '¼': "1/4", '¾': "3/4", '⅓': "1/3", '⅔': "2/3", '…': "...",
+	} {
+		table[r] = s
+	}
+
+	// Full-width forms of the printable ASCII characters (Ａ, １, ＃)
+	for r := rune(0xFF01); r <= 0xFF5E; r++ {
+		table[r] = string(r - 0xFF01 + '!')
+	}
+
+	// Latin letters with diacritics become the letter they are built on ("ñ" becomes "n")
+	for _, fields := range parseTable(latinLetterData) {
+		for _, r := range fields[1] {
+			table[r] = fields[0]
+		}
+	}
+
+	return table
+}()
+
+// fractions are the transliterations written after a house number with a space ("123 1/2")
+var fractions = map[rune]bool{'½': true, '¼': true, '¾': true, '⅓': true, '⅔': true}
+
+// asciiFor returns the ASCII replacement for a non-ASCII character
+// Combining marks, as in decomposed input ("e" followed by U+0301), are removed
+func asciiFor(r rune) (string, bool) {
+	if to, ok := transliterations[r]; ok {
+		return to, true
+	}
+	if unicode.Is(unicode.Mn, r) {
+		return "", true
+	}
+	return "", false
+}
+
+// Transliterate replaces accented letters, curly quotes, dashes, unusual spaces and
+// full-width characters in every field with their plain ASCII equivalents ("Peñasco" becomes
+// "Penasco"), and returns a record of each change so it can be shown to the user
+// Characters without an ASCII equivalent, such as "北", are left alone; ValidateAddress rejects
+// them with a NonASCIIError
+func Transliterate(address Address) (Address, []Transliteration) {
+	var changes []Transliteration
+	for _, field := range addressFields {
+		p := address.fieldPtr(field)
+		value, fieldChanges := transliterateValue(field, *p)
+		*p = value
+		changes = append(changes, fieldChanges...)
+	}
+	return address, changes
+}
+
+// checkASCII returns a NonASCIIError listing every non-ASCII character in the address
+func checkASCII(address Address) error {
+	return findNonASCII(address, false)
+}
+
+// checkTransliterable returns a NonASCIIError listing every character in the address that
+// Transliterate can't replace with ASCII
+func checkTransliterable(address Address) error {
+	return findNonASCII(address, true)
+}
+
+// findNonASCII lists the non-ASCII characters in the address, or only those without an ASCII
+// equivalent, as a NonASCIIError
+func findNonASCII(address Address, untransliterable bool) error {
+	var chars []Transliteration
+	for _, field := range addressFields {
+		for offset, r := range address.Get(field) {
+			if r <= unicode.MaxASCII {
+				continue
+			}
+			to, ok := asciiFor(r)
+			if ok && untransliterable {
+				continue
+			}
+			chars = append(chars, Transliteration{
+				Field:  field,
+				Offset: offset,
+				From:   string(r),
+				To:     to,
+			})
+		}
+	}
+	if len(chars) == 0 {
+		return nil
+	}
+	return &NonASCIIError{Characters: chars}
+}
+
+// transliterateValue transliterates a single field value, returning the new value and the replacements made
+func transliterateValue(field Field, s string) (string, []Transliteration) {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	var changes []Transliteration
+	var prev rune
+	for offset, r := range s {
+		to, ok := asciiFor(r)
+		if !ok {
+			b.WriteRune(r)
+			prev = r
+			continue
+		}
+		// "123½" is house number 123 1/2, not 1231/2
+		if fractions[r] && unicode.IsDigit(prev) {
+			to = " " + to
+		}
+		b.WriteString(to)
+		if to != "" {
+			prev = rune(to[len(to)-1])
+		}
+		changes = append(changes, Transliteration{
+			Field:  field,
+			Offset: offset,
+			From:   string(r),
+			To:     to,
+		})
+	}
+
+	return b.String(), changes
+}
@@ -0,0 +1,42 @@
+package uspsaddr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Peñasco", "Penasco"},
+		{"Łódź", "Lodz"},
+		{"Øster Ħal Đurđevac", "Oster Hal Durdevac"},
+		{"Nguyễn", "Nguyen"},
+		{"Café", "Cafe"},
+		{"Straße", "Strasse"},
+		{"123½ Main St", "123 1/2 Main St"},
+		{"½ Mile Rd", "1/2 Mile Rd"},
+		{"O’Brien “The Rock” St", `O'Brien "The Rock" St`},
+		{"１２３ Ｍａｉｎ", "123 Main"},
+		{"12\u00a0Elm\u200b St", "12 Elm St"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, _ := Transliterate(Address{StreetAddress: tt.input})
+			if got.StreetAddress != tt.want {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.input, got.StreetAddress, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInputRejectsUntransliterable(t *testing.T) {
+	err := ValidateInput(Address{StreetAddress: "1 北 St", City: "Boulder", State: "CO"})
+	var nonASCII *NonASCIIError
+	if !errors.As(err, &nonASCII) || len(nonASCII.Characters) != 1 || nonASCII.Characters[0].From != "北" {
+		t.Errorf("ValidateInput() = %v, want a *NonASCIIError for 北", err)
+	}
+}
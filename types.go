@@ -1,5 +1,7 @@
 package uspsaddr
 
+import "time"
+
 // Address represents a canonicalized USPS address
 type Address struct {
 	// Firm/business name at the address
@@ -33,11 +35,107 @@ type Address struct {
 	Urbanization string
 }
 
+// Field identifies a field of an Address
+type Field string
+
+// Address fields
+const (
+	FieldFirm                      Field = "Firm"
+	FieldStreetAddress             Field = "StreetAddress"
+	FieldStreetAddressAbbreviation Field = "StreetAddressAbbreviation"
+	FieldSecondaryAddress          Field = "SecondaryAddress"
+	FieldCity                      Field = "City"
+	FieldCityAbbreviation          Field = "CityAbbreviation"
+	FieldState                     Field = "State"
+	FieldZIPCode                   Field = "ZIPCode"
+	FieldZIPPlus4                  Field = "ZIPPlus4"
+	FieldUrbanization              Field = "Urbanization"
+)
+
+// addressFields lists every Address field in declaration order
+var addressFields = []Field{
+	FieldFirm,
+	FieldStreetAddress,
+	FieldStreetAddressAbbreviation,
+	FieldSecondaryAddress,
+	FieldCity,
+	FieldCityAbbreviation,
+	FieldState,
+	FieldZIPCode,
+	FieldZIPPlus4,
+	FieldUrbanization,
+}
+
+// fieldPtr returns a pointer to the given field of the address, or nil for an unknown field
+func (a *Address) fieldPtr(field Field) *string {
+	switch field {
+	case FieldFirm:
+		return &a.Firm
+	case FieldStreetAddress:
+		return &a.StreetAddress
+	case FieldStreetAddressAbbreviation:
+		return &a.StreetAddressAbbreviation
+	case FieldSecondaryAddress:
+		return &a.SecondaryAddress
+	case FieldCity:
+		return &a.City
+	case FieldCityAbbreviation:
+		return &a.CityAbbreviation
+	case FieldState:
+		return &a.State
+	case FieldZIPCode:
+		return &a.ZIPCode
+	case FieldZIPPlus4:
+		return &a.ZIPPlus4
+	case FieldUrbanization:
+		return &a.Urbanization
+	}
+	return nil
+}
+
+// Get returns the value of the given field, or "" for an unknown field
+func (a Address) Get(field Field) string {
+	if p := a.fieldPtr(field); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// CityState contains the city and state that USPS associates with a ZIP code
+type CityState struct {
+	// City name
+	City string
+
+	// Two-letter state code
+	State string
+
+	// 5-digit ZIP code
+	ZIPCode string
+}
+
 // ValidationResult contains the results of address validation
 type ValidationResult struct {
 	// The canonicalized address
 	Address Address
 
+	// The normalized input that was sent to USPS
+	Input Address
+
+	// Set when a secondary unit was moved out of the street address before the request
+	SecondarySplit *SecondarySplit
+
+	// Characters in the input that were replaced with ASCII before the request
+	Transliterations []Transliteration
+
+	// Whether this is an APO/FPO/DPO military address
+	Military bool
+
+	// Set when the result is an alternative found by changing the input (see Config.CandidateBudget)
+	Candidate *Candidate
+
+	// The kind of delivery the canonical address receives (street, PO Box, rural route...)
+	AddressType AddressType
+
 	// Codes indicating how to improve the address
 	Corrections []Correction
 
@@ -49,14 +147,22 @@ type ValidationResult struct {
 
 	// Additional information about the address
 	AdditionalInfo *AdditionalInfo
+
+	// Set when Config.Policy is set: the decision and the rules that fired
+	Policy *PolicyResult
 }
 
 // Correction indicates how to improve the address input
 type Correction struct {
 	Code string
 	Text string
-	// UserMessage provides a user-friendly explanation of the correction
-	// This is derived from the correction code and DPV confirmation
+
+	// How much the correction matters, and what the user should do about it
+	Severity Severity
+	Action   Action
+
+	// UserMessage provides a user-friendly explanation of the correction in Config.Locale
+	// This is derived from the correction code and DPV confirmation (see LookupCode)
 	UserMessage string
 }
 
@@ -64,6 +170,13 @@ type Correction struct {
 type Match struct {
 	Code string
 	Text string
+
+	// How much the match code matters, and what the user should do about it
+	Severity Severity
+	Action   Action
+
+	// UserMessage provides a user-friendly explanation of the match code in Config.Locale
+	UserMessage string
 }
 
 // AdditionalInfo contains additional address information
@@ -75,23 +188,45 @@ type AdditionalInfo struct {
 	CarrierRoute string
 
 	// DPV (Delivery Point Validation) confirmation indicator
-	DPVConfirmation string
+	DPVConfirmation DPV
 
 	// DPVCMRA (Commercial Mail Receiving Agency) indicator
-	DPVCMRA string
+	DPVCMRA Flag
 
 	// Business flag
-	Business string
+	Business Flag
 
 	// Central delivery flag
-	CentralDeliveryPoint string
+	CentralDeliveryPoint Flag
 
 	// Vacant flag
-	Vacant string
+	Vacant Flag
 }
 
 // Error represents a USPS API error
+// Use errors.Is with the sentinel errors (ErrAddressNotFound, ErrRateLimited, ...) to check the kind of error
 type Error struct {
+	// Error code from the response body
+	Code   string
+	Title  string
+	Detail string
+	Source *ErrorSource
+
+	// Every field-level error USPS reported; Title, Detail and Source above come from the first one
+	Errors []ErrorDetail
+
+	// HTTP status code of the response, or 0 if the error didn't come from a response
+	HTTPStatus int
+
+	// How long USPS asked us to wait before retrying (Retry-After), or 0 if it didn't say
+	RetryAfter time.Duration
+
+	// Version of the API that raised the error
+	APIVersion string
+}
+
+// ErrorDetail is one field-level error from a USPS error response
+type ErrorDetail struct {
 	Status string
 	Code   string
 	Title  string
@@ -101,8 +236,28 @@ type Error struct {
 
 // ErrorSource identifies the source of an error
 type ErrorSource struct {
+	// USPS request parameter name ("streetAddress")
 	Parameter string
-	Example   string
+
+	// The Address field the parameter corresponds to, or "" if it isn't an address field
+	Field Field
+
+	// Example of a valid value
+	Example string
+}
+
+// Fields returns the Address fields USPS reported errors for, without duplicates
+func (e *Error) Fields() []Field {
+	var fields []Field
+	seen := map[Field]bool{}
+	for _, detail := range e.Errors {
+		if detail.Source == nil || detail.Source.Field == "" || seen[detail.Source.Field] {
+			continue
+		}
+		seen[detail.Source.Field] = true
+		fields = append(fields, detail.Source.Field)
+	}
+	return fields
 }
 
 func (e *Error) Error() string {
@@ -0,0 +1,182 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// fieldConstraint is a USPS API limit on one request parameter
+// The tables of these in constraints.gen.go are generated from the OpenAPI spec by `make generate`
+type fieldConstraint struct {
+	Field Field
+
+	// USPS query parameter name
+	Parameter string
+
+	Required bool
+
+	// Length limits in characters; 0 means no limit
+	MinLength int
+	MaxLength int
+
+	// Pattern the whole value must match, if any
+	Pattern *regexp.Regexp
+}
+
+// FieldError describes a single field that USPS would reject
+type FieldError struct {
+	Field Field
+
+	// USPS query parameter name
+	Parameter string
+
+	// The rule that was broken: "required", "minLength", "maxLength" or "pattern"
+	Rule string
+
+	// The offending value
+	Value string
+
+	Message string
+
+	// The underlying error, such as an InvalidStateError, if any
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FieldError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// ValidationErrors lists every problem found with an address before sending it to USPS
+// errors.As can be used to find a specific error, such as an *InvalidStateError, in the list
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// fieldLabels are the names used for fields in error messages
+var fieldLabels = map[Field]string{
+	FieldFirm:                      "firm",
+	FieldStreetAddress:             "street address",
+	FieldStreetAddressAbbreviation: "street address abbreviation",
+	FieldSecondaryAddress:          "secondary address",
+	FieldCity:                      "city",
+	FieldCityAbbreviation:          "city abbreviation",
+	FieldState:                     "2 letter state abbreviation",
+	FieldZIPCode:                   "5 digit ZIP code",
+	FieldZIPPlus4:                  "4 digit ZIP+4 code",
+	FieldUrbanization:              "urbanization",
+}
+
+// Validate checks the address against the USPS API's request constraints without calling USPS
+// It returns ValidationErrors listing every violation, or nil if the address can be sent as is
+// The address is checked exactly as given; use ValidateInput to clean it up first
+func (a Address) Validate() error {
+	errs := checkConstraints(a, addressConstraints)
+
+	// The spec asks for either a city or a ZIP code along with the street and state
+	if a.City == "" && a.ZIPCode == "" {
+		errs = append(errs, &FieldError{
+			Field:     FieldCity,
+			Parameter: "city",
+			Rule:      "required",
+			Message:   "city or ZIP code is required",
+		})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateInput cleans up an address the same way ValidateAddress does and checks it
+// against the USPS API's request constraints, returning ValidationErrors if there are problems
+// Characters that can't be transliterated to ASCII are reported with a NonASCIIError
+func ValidateInput(address Address) error {
+	if err := checkTransliterable(address); err != nil {
+		return err
+	}
+	return prepareAddress(address).Address.Validate()
+}
+
+// checkConstraints returns an error for every constraint the address breaks
+func checkConstraints(address Address, constraints []fieldConstraint) ValidationErrors {
+	var errs ValidationErrors
+
+	for _, c := range constraints {
+		value := address.Get(c.Field)
+		label := fieldLabels[c.Field]
+
+		fieldErr := &FieldError{
+			Field:     c.Field,
+			Parameter: c.Parameter,
+			Value:     value,
+		}
+
+		length := utf8.RuneCountInString(value)
+		switch {
+		case value == "":
+			if !c.Required {
+				continue
+			}
+			fieldErr.Rule = "required"
+			fieldErr.Message = fmt.Sprintf("%s is required", label)
+
+		case c.MaxLength > 0 && length > c.MaxLength:
+			fieldErr.Rule = "maxLength"
+			fieldErr.Message = fmt.Sprintf("%s is %d characters long, the limit is %d", label, length, c.MaxLength)
+
+		case c.MinLength > 0 && length < c.MinLength:
+			fieldErr.Rule = "minLength"
+			fieldErr.Message = fmt.Sprintf("%s must be at least %d characters long", label, c.MinLength)
+
+		case c.Pattern != nil && !c.Pattern.MatchString(value):
+			fieldErr.Rule = "pattern"
+			fieldErr.Message = fmt.Sprintf("%q is not a valid %s", value, label)
+
+		default:
+			continue
+		}
+
+		// Keep the specific error type callers already check for
+		if c.Field == FieldState && fieldErr.Rule != "required" {
+			stateErr := &InvalidStateError{State: value}
+			if info, ok := SuggestState(value); ok {
+				stateErr.SuggestedState = info.Code
+			}
+			fieldErr.Err = stateErr
+			fieldErr.Message = fieldErr.Err.Error()
+		}
+
+		errs = append(errs, fieldErr)
+	}
+
+	return errs
+}
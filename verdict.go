@@ -0,0 +1,175 @@
+package uspsaddr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcome is the overall deliverability of a validated address
+type Outcome int
+
+const (
+	// OutcomeUnknown is the zero value: no verdict was made
+	// ValidationResult.Verdict never returns it
+	OutcomeUnknown Outcome = iota
+
+	// OutcomeUndeliverable means USPS couldn't confirm the address as a delivery point
+	OutcomeUndeliverable
+
+	// OutcomeDeliverable means the address, including any secondary number, was confirmed
+	OutcomeDeliverable
+
+	// OutcomeDeliverableMissingSecondary means the building was confirmed but it needs a
+	// secondary number (apartment, suite) that wasn't given
+	OutcomeDeliverableMissingSecondary
+
+	// OutcomeSecondaryUnconfirmed means the building was confirmed but the secondary number wasn't
+	OutcomeSecondaryUnconfirmed
+
+	// OutcomeAmbiguous means more than one address matched and USPS picked one
+	OutcomeAmbiguous
+
+	// OutcomeNoStreetDelivery means the address exists but USPS doesn't deliver to it;
+	// the recipient gets their mail somewhere else, such as a PO Box
+	OutcomeNoStreetDelivery
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeUnknown:
+		return "unknown"
+	case OutcomeUndeliverable:
+		return "undeliverable"
+	case OutcomeDeliverable:
+		return "deliverable"
+	case OutcomeDeliverableMissingSecondary:
+		return "deliverable, missing secondary"
+	case OutcomeSecondaryUnconfirmed:
+		return "secondary unconfirmed"
+	case OutcomeAmbiguous:
+		return "ambiguous"
+	case OutcomeNoStreetDelivery:
+		return "no street delivery"
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Reason is something in the USPS response that went into a Verdict
+type Reason string
+
+const (
+	// ReasonExactMatch is match code 31: a single, exact match
+	ReasonExactMatch Reason = "exact match"
+
+	// ReasonMultipleMatches is correction code 22: more than one address matched
+	ReasonMultipleMatches Reason = "more than one address matches"
+
+	// ReasonMoreInformationNeeded is correction code 32: a secondary number is needed
+	ReasonMoreInformationNeeded Reason = "more information is needed"
+
+	// ReasonDPVConfirmed means the DPV indicator is Y
+	ReasonDPVConfirmed Reason = "DPV confirmed"
+
+	// ReasonMissingSecondary means the DPV indicator is D
+	ReasonMissingSecondary Reason = "secondary number missing"
+
+	// ReasonSecondaryUnconfirmed means the DPV indicator is S
+	ReasonSecondaryUnconfirmed Reason = "secondary number not confirmed"
+
+	// ReasonDPVNotConfirmed means the DPV indicator is N, or a value that isn't recognized
+	ReasonDPVNotConfirmed Reason = "not DPV confirmed"
+
+	// ReasonNoDPV means USPS didn't return a DPV indicator
+	ReasonNoDPV Reason = "no DPV indicator"
+
+	// ReasonNoStreetDelivery means the carrier route is R777 or R779, which don't get street delivery
+	ReasonNoStreetDelivery Reason = "no street delivery on carrier route"
+
+	// ReasonVacant means the address has been vacant for 90 days or more
+	ReasonVacant Reason = "vacant"
+
+	// ReasonCMRA means the address is a Commercial Mail Receiving Agency, such as a UPS Store
+	ReasonCMRA Reason = "commercial mail receiving agency"
+)
+
+// Verdict is the deliverability of a validated address, with the reasons for it
+type Verdict struct {
+	Outcome Outcome
+
+	// Everything in the response that went into the outcome, or is worth knowing about it
+	// Vacant and CMRA addresses are still deliverable, but are listed here
+	Reasons []Reason
+}
+
+// noStreetDeliveryRoutes are the carrier routes the spec says may not receive delivery
+var noStreetDeliveryRoutes = map[string]bool{
+	"R777": true,
+	"R779": true,
+}
+
+// Verdict combines the match and correction codes, DPV indicator, carrier route, vacancy and
+// CMRA flags into a single deliverability outcome
+func (r ValidationResult) Verdict() Verdict {
+	var v Verdict
+
+	for _, m := range r.Matches {
+		if m.Code == "31" {
+			v.Reasons = append(v.Reasons, ReasonExactMatch)
+		}
+	}
+
+	ambiguous := hasCorrection(r, "22")
+	if ambiguous {
+		v.Reasons = append(v.Reasons, ReasonMultipleMatches)
+	}
+	moreInformation := hasCorrection(r, "32")
+	if moreInformation {
+		v.Reasons = append(v.Reasons, ReasonMoreInformationNeeded)
+	}
+
+	var info AdditionalInfo
+	if r.AdditionalInfo != nil {
+		info = *r.AdditionalInfo
+	}
+
+	switch info.DPVConfirmation {
+	case DPVConfirmed:
+		v.Reasons = append(v.Reasons, ReasonDPVConfirmed)
+	case DPVMissingSecondary:
+		v.Reasons = append(v.Reasons, ReasonMissingSecondary)
+	case DPVSecondaryUnconfirmed:
+		v.Reasons = append(v.Reasons, ReasonSecondaryUnconfirmed)
+	case DPVUnknown:
+		v.Reasons = append(v.Reasons, ReasonNoDPV)
+	default:
+		v.Reasons = append(v.Reasons, ReasonDPVNotConfirmed)
+	}
+
+	noStreetDelivery := noStreetDeliveryRoutes[strings.ToUpper(info.CarrierRoute)]
+	if noStreetDelivery {
+		v.Reasons = append(v.Reasons, ReasonNoStreetDelivery)
+	}
+	if info.Vacant.Yes() {
+		v.Reasons = append(v.Reasons, ReasonVacant)
+	}
+	if info.DPVCMRA.Yes() {
+		v.Reasons = append(v.Reasons, ReasonCMRA)
+	}
+
+	switch {
+	case ambiguous:
+		v.Outcome = OutcomeAmbiguous
+	case !info.DPVConfirmation.Confirmed():
+		v.Outcome = OutcomeUndeliverable
+	case noStreetDelivery:
+		v.Outcome = OutcomeNoStreetDelivery
+	case info.DPVConfirmation.MissingSecondary(), moreInformation && info.DPVConfirmation == DPVConfirmed:
+		v.Outcome = OutcomeDeliverableMissingSecondary
+	case info.DPVConfirmation.SecondaryUnconfirmed():
+		v.Outcome = OutcomeSecondaryUnconfirmed
+	default:
+		v.Outcome = OutcomeDeliverable
+	}
+
+	return v
+}
@@ -0,0 +1,72 @@
+package uspsaddr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVerdict(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      ValidationResult
+		want        Outcome
+		wantReasons []Reason
+	}{
+		{
+			name:        "exact match",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVConfirmed}, Matches: []Match{{Code: "31"}}},
+			want:        OutcomeDeliverable,
+			wantReasons: []Reason{ReasonExactMatch, ReasonDPVConfirmed},
+		},
+		{
+			name:        "no additional info",
+			result:      ValidationResult{},
+			want:        OutcomeUndeliverable,
+			wantReasons: []Reason{ReasonNoDPV},
+		},
+		{
+			name:        "ambiguous",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVConfirmed}, Corrections: []Correction{{Code: "22"}}},
+			want:        OutcomeAmbiguous,
+			wantReasons: []Reason{ReasonMultipleMatches, ReasonDPVConfirmed},
+		},
+		{
+			name:        "missing secondary",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVConfirmed}, Corrections: []Correction{{Code: "32"}}},
+			want:        OutcomeDeliverableMissingSecondary,
+			wantReasons: []Reason{ReasonMoreInformationNeeded, ReasonDPVConfirmed},
+		},
+		{
+			name:        "secondary unconfirmed",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVSecondaryUnconfirmed}},
+			want:        OutcomeSecondaryUnconfirmed,
+			wantReasons: []Reason{ReasonSecondaryUnconfirmed},
+		},
+		{
+			name:        "no street delivery, vacant",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVConfirmed, CarrierRoute: "R777", Vacant: FlagYes}},
+			want:        OutcomeNoStreetDelivery,
+			wantReasons: []Reason{ReasonDPVConfirmed, ReasonNoStreetDelivery, ReasonVacant},
+		},
+		{
+			name:        "not confirmed",
+			result:      ValidationResult{AdditionalInfo: &AdditionalInfo{DPVConfirmation: DPVNotConfirmed}},
+			want:        OutcomeUndeliverable,
+			wantReasons: []Reason{ReasonDPVNotConfirmed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.result.Verdict()
+			if got.Outcome != tt.want || !slices.Equal(got.Reasons, tt.wantReasons) {
+				t.Errorf("Verdict() = %v %v, want %v %v", got.Outcome, got.Reasons, tt.want, tt.wantReasons)
+			}
+		})
+	}
+
+	var unset Verdict
+	if unset.Outcome != OutcomeUnknown {
+		t.Errorf("zero Verdict has Outcome %v, want %v", unset.Outcome, OutcomeUnknown)
+	}
+}
@@ -0,0 +1,111 @@
+package uspsaddr
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed data/zip_prefixes.csv
+var zipPrefixData string
+
+// ZIPStateMismatchError is returned when a ZIP code doesn't belong to the state given with it
+type ZIPStateMismatchError struct {
+	ZIPCode string
+	State   string
+
+	// The state the ZIP code belongs to, which is most likely the one that was meant
+	SuggestedState string
+}
+
+func (e *ZIPStateMismatchError) Error() string {
+	return fmt.Sprintf("ZIP code %s is in %s, not %s", e.ZIPCode, e.SuggestedState, e.State)
+}
+
+func (e *ZIPStateMismatchError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// zipRange is a range of 5-digit ZIP codes assigned to a state
+type zipRange struct {
+	Low, High int
+	State     string
+}
+
+// zipRanges lists the ranges in data/zip_prefixes.csv, most specific (smallest) first
+var zipRanges = func() []zipRange {
+	var ranges []zipRange
+	for _, fields := range parseTable(zipPrefixData) {
+		for _, r := range fields[1:] {
+			low, high, _ := strings.Cut(r, "-")
+			if high == "" {
+				high = low
+			}
+			ranges = append(ranges, zipRange{
+				Low:   zipRangeBound(low, "00"),
+				High:  zipRangeBound(high, "99"),
+				State: fields[0],
+			})
+		}
+	}
+
+	// Stable so the table order breaks ties
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].High-ranges[i].Low < ranges[j].High-ranges[j].Low
+	})
+	return ranges
+}()
+
+// zipRangeBound converts a ZIP3 prefix or 5-digit ZIP code into a 5-digit bound,
+// padding a prefix with pad ("00" for the low end, "99" for the high end)
+func zipRangeBound(s, pad string) int {
+	if len(s) == 3 {
+		s += pad
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		panic(fmt.Sprintf("bad ZIP range %q in data/zip_prefixes.csv", s))
+	}
+	return n
+}
+
+// StateForZIP returns the state, territory or military region a ZIP code belongs to, going by its prefix
+// Only the first 5 digits are used, so ZIP+4 codes ("80302-1234") are accepted too
+func StateForZIP(zip string) (StateInfo, bool) {
+	zip = strings.TrimSpace(zip)
+	if len(zip) < 5 || !zipCodePattern.MatchString(zip[:5]) {
+		return StateInfo{}, false
+	}
+	n, _ := strconv.Atoi(zip[:5])
+
+	for _, r := range zipRanges {
+		if n >= r.Low && n <= r.High {
+			return stateByCode[r.State], true
+		}
+	}
+	return StateInfo{}, false
+}
+
+// CheckZIPState checks, without calling USPS, that a ZIP code belongs to the state
+// It returns a *ZIPStateMismatchError suggesting the ZIP code's state if they disagree,
+// which catches swapped or mistyped fields before a request is made
+// ZIP codes outside every known range, and states that aren't recognized, are not reported
+func CheckZIPState(zip, state string) error {
+	info, ok := StateForZIP(zip)
+	if !ok {
+		return nil
+	}
+
+	code, ok := stateCode(state)
+	if !ok || code == info.Code {
+		return nil
+	}
+
+	return &ZIPStateMismatchError{
+		ZIPCode:        strings.TrimSpace(zip),
+		State:          code,
+		SuggestedState: info.Code,
+	}
+}